@@ -0,0 +1,377 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"context"
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+)
+
+// NewArrayBlockingQueue 创建一个基于环形数组的有界阻塞队列
+// 参数 capacity 为队列容量，必须大于0
+func NewArrayBlockingQueue[E comparable](capacity int) BlockingQueue[E] {
+	if capacity < 1 {
+		panic(fmt.Sprintf("collect: invalid queue capacity %d", capacity))
+	}
+	return &arrayBlockingQueue[E]{
+		items: make([]E, capacity),
+	}
+}
+
+type arrayBlockingQueue[E comparable] struct {
+	// items 环形数组
+	// takeIndex 下一次 Take 的位置，putIndex 下一次 Put 的位置
+	items               []E
+	takeIndex, putIndex int
+	count               int
+	zeroVal             E
+
+	lock              sync.Mutex
+	notEmpty, notFull notifier
+}
+
+func (q *arrayBlockingQueue[E]) Size() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.count
+}
+
+func (q *arrayBlockingQueue[E]) IsEmpty() bool {
+	return q.Size() == 0
+}
+
+func (q *arrayBlockingQueue[E]) Contains(e E) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.indexOf(e) >= 0
+}
+
+func (q *arrayBlockingQueue[E]) Iterator() Iterator[E] {
+	return newSnapshotIterator[E](q)
+}
+
+func (q *arrayBlockingQueue[E]) ToArray() []E {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.count == 0 {
+		return nil
+	}
+	res := make([]E, q.count)
+	for i, j := 0, q.takeIndex; i < q.count; i, j = i+1, q.inc(j) {
+		res[i] = q.items[j]
+	}
+	return res
+}
+
+func (q *arrayBlockingQueue[E]) Add(e E) bool {
+	return q.Put(e)
+}
+
+func (q *arrayBlockingQueue[E]) Remove(e E) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	i := q.indexOf(e)
+	if i < 0 {
+		return false
+	}
+	q.removeAt(i)
+	return true
+}
+
+func (q *arrayBlockingQueue[E]) ContainsAll(c Collection[E]) bool {
+	itr := c.Iterator()
+	for itr.HasNext() {
+		if e, err := itr.Next(); err != nil || !q.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddAll 将指定集合中的元素依次加入队列，队列已满时剩余的元素会被丢弃
+func (q *arrayBlockingQueue[E]) AddAll(c Collection[E]) {
+	for _, e := range c.ToArray() {
+		if !q.Put(e) {
+			return
+		}
+	}
+}
+
+func (q *arrayBlockingQueue[E]) RemoveAll(c Collection[E]) int {
+	return q.RemoveIf(func(e E) bool {
+		return c.Contains(e)
+	})
+}
+
+// RemoveIf 在队列的快照上调用 filter，调用时不持有锁，filter 中可以访问队列
+// 之后删除队列中所有与 filter 返回 true 的元素相等的元素，包括快照之后加入的相等元素
+func (q *arrayBlockingQueue[E]) RemoveIf(filter Predicate[E]) int {
+	matched := matchSnapshot(q.ToArray(), filter)
+	if len(matched) == 0 {
+		return 0
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	var kept int
+	for i, j := 0, q.takeIndex; i < q.count; i, j = i+1, q.inc(j) {
+		e := q.items[j]
+		if _, ok := matched[e]; !ok {
+			q.items[(q.takeIndex+kept)%len(q.items)] = e
+			kept++
+		}
+	}
+	cnt := q.count - kept
+	if cnt == 0 {
+		return 0
+	}
+	for i, j := kept, (q.takeIndex+kept)%len(q.items); i < q.count; i, j = i+1, q.inc(j) {
+		q.items[j] = q.zeroVal
+	}
+	q.count = kept
+	q.putIndex = (q.takeIndex + kept) % len(q.items)
+	q.notFull.broadcast()
+	return cnt
+}
+
+func (q *arrayBlockingQueue[E]) RetainAll(c Collection[E]) int {
+	return q.RemoveIf(func(e E) bool {
+		return !c.Contains(e)
+	})
+}
+
+func (q *arrayBlockingQueue[E]) Clear() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for i := range q.items {
+		q.items[i] = q.zeroVal
+	}
+	q.takeIndex, q.putIndex, q.count = 0, 0, 0
+	q.notFull.broadcast()
+}
+
+func (q *arrayBlockingQueue[E]) Equals(c Collection[E]) bool {
+	return equals[E](q, c)
+}
+
+// ForEach 迭代队列中元素的快照，迭代过程中不持有锁
+func (q *arrayBlockingQueue[E]) ForEach(f Consumer[E]) error {
+	for _, e := range q.ToArray() {
+		if err := f(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// All 迭代队列中元素的快照，迭代过程中不持有锁
+func (q *arrayBlockingQueue[E]) All() iter.Seq[E] {
+	return slices.Values(q.ToArray())
+}
+
+func (q *arrayBlockingQueue[E]) GetEqualComparator() constraints.EqualComparator[E] {
+	return comparableEqual[E]()
+}
+
+func (q *arrayBlockingQueue[E]) Put(e E) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.count == len(q.items) {
+		return false
+	}
+	q.enqueue(e)
+	return true
+}
+
+func (q *arrayBlockingQueue[E]) Take() (E, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.count == 0 {
+		return q.zeroVal, false
+	}
+	return q.dequeue(), true
+}
+
+func (q *arrayBlockingQueue[E]) Peek() (E, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.count == 0 {
+		return q.zeroVal, false
+	}
+	return q.items[q.takeIndex], true
+}
+
+func (q *arrayBlockingQueue[E]) PutCtx(ctx context.Context, e E) error {
+	q.lock.Lock()
+	for q.count == len(q.items) {
+		ch := q.notFull.wait()
+		q.lock.Unlock()
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		q.lock.Lock()
+	}
+	q.enqueue(e)
+	q.lock.Unlock()
+	return nil
+}
+
+func (q *arrayBlockingQueue[E]) TakeCtx(ctx context.Context) (E, error) {
+	q.lock.Lock()
+	for q.count == 0 {
+		ch := q.notEmpty.wait()
+		q.lock.Unlock()
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return q.zeroVal, ctx.Err()
+		}
+		q.lock.Lock()
+	}
+	e := q.dequeue()
+	q.lock.Unlock()
+	return e, nil
+}
+
+func (q *arrayBlockingQueue[E]) Offer(e E, timeout time.Duration) bool {
+	if timeout <= 0 {
+		return q.Put(e)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return q.PutCtx(ctx, e) == nil
+}
+
+func (q *arrayBlockingQueue[E]) Poll(timeout time.Duration) (E, bool) {
+	if timeout <= 0 {
+		return q.Take()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	e, err := q.TakeCtx(ctx)
+	return e, err == nil
+}
+
+func (q *arrayBlockingQueue[E]) RemainingCapacity() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.items) - q.count
+}
+
+func (q *arrayBlockingQueue[E]) DrainTo(c Collection[E], max int) int {
+	q.lock.Lock()
+	n := q.count
+	if max >= 0 && max < n {
+		n = max
+	}
+	if n == 0 {
+		q.lock.Unlock()
+		return 0
+	}
+	drained := make([]E, n)
+	for i := 0; i < n; i++ {
+		drained[i] = q.dequeue()
+	}
+	q.lock.Unlock()
+	c.AddAll(wrapArrayList[E](drained))
+	return n
+}
+
+func (q *arrayBlockingQueue[E]) String() string {
+	return fmt.Sprintf("%v", q.ToArray())
+}
+
+// enqueue 在 putIndex 位置插入元素，调用前必须持有锁并确保队列未满
+func (q *arrayBlockingQueue[E]) enqueue(e E) {
+	q.items[q.putIndex] = e
+	q.putIndex = q.inc(q.putIndex)
+	q.count++
+	q.notEmpty.broadcast()
+}
+
+// dequeue 移除并返回 takeIndex 位置的元素，调用前必须持有锁并确保队列不为空
+func (q *arrayBlockingQueue[E]) dequeue() E {
+	e := q.items[q.takeIndex]
+	q.items[q.takeIndex] = q.zeroVal
+	q.takeIndex = q.inc(q.takeIndex)
+	q.count--
+	q.notFull.broadcast()
+	return e
+}
+
+// removeAt 删除环形数组中指定下标的元素，后面的元素依次向前移动
+func (q *arrayBlockingQueue[E]) removeAt(i int) {
+	if i == q.takeIndex {
+		q.dequeue()
+		return
+	}
+	for {
+		next := q.inc(i)
+		if next == q.putIndex {
+			q.items[i] = q.zeroVal
+			q.putIndex = i
+			break
+		}
+		q.items[i] = q.items[next]
+		i = next
+	}
+	q.count--
+	q.notFull.broadcast()
+}
+
+// indexOf 返回元素在环形数组中的下标，不存在返回-1
+func (q *arrayBlockingQueue[E]) indexOf(e E) int {
+	for i, j := 0, q.takeIndex; i < q.count; i, j = i+1, q.inc(j) {
+		if q.items[j] == e {
+			return j
+		}
+	}
+	return -1
+}
+
+func (q *arrayBlockingQueue[E]) inc(i int) int {
+	i++
+	if i == len(q.items) {
+		return 0
+	}
+	return i
+}
+
+// matchSnapshot 对快照中每个不同的元素调用一次 filter，返回 filter 返回 true 的元素
+// 阻塞队列使用它在不持有锁的情况下执行调用方的 filter，避免 filter 访问队列时死锁
+func matchSnapshot[E comparable](values []E, filter Predicate[E]) map[E]struct{} {
+	tested := make(map[E]bool, len(values))
+	matched := make(map[E]struct{})
+	for _, e := range values {
+		if _, ok := tested[e]; ok {
+			continue
+		}
+		tested[e] = filter(e)
+		if tested[e] {
+			matched[e] = struct{}{}
+		}
+	}
+	return matched
+}
@@ -0,0 +1,175 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_arrayBlockingQueue_putTake(t *testing.T) {
+	q := NewArrayBlockingQueue[int](3)
+	for _, v := range []int{1, 2, 3} {
+		if !q.Put(v) {
+			t.Errorf("Put(%d) = false, want true", v)
+		}
+	}
+	if q.Put(4) {
+		t.Error("Put(4) = true, want false")
+	}
+	if got := q.RemainingCapacity(); got != 0 {
+		t.Errorf("RemainingCapacity() = %d, want 0", got)
+	}
+	if e, ok := q.Peek(); !ok || e != 1 {
+		t.Errorf("Peek() = %v, %v, want 1, true", e, ok)
+	}
+	if e, ok := q.Take(); !ok || e != 1 {
+		t.Errorf("Take() = %v, %v, want 1, true", e, ok)
+	}
+	// 环形数组回绕
+	q.Put(4)
+	if got := q.ToArray(); !reflect.DeepEqual(got, []int{2, 3, 4}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{2, 3, 4})
+	}
+	for _, want := range []int{2, 3, 4} {
+		if e, ok := q.Take(); !ok || e != want {
+			t.Errorf("Take() = %v, %v, want %v, true", e, ok, want)
+		}
+	}
+	if _, ok := q.Take(); ok {
+		t.Error("Take() ok = true, want false")
+	}
+}
+
+func Test_arrayBlockingQueue_remove(t *testing.T) {
+	q := NewArrayBlockingQueue[int](5)
+	q.Put(0)
+	q.Take()
+	q.Take()
+	for _, v := range []int{1, 2, 3, 4, 5} {
+		q.Put(v)
+	}
+	if !q.Remove(3) {
+		t.Error("Remove(3) = false, want true")
+	}
+	if q.Remove(10) {
+		t.Error("Remove(10) = true, want false")
+	}
+	if got := q.ToArray(); !reflect.DeepEqual(got, []int{1, 2, 4, 5}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{1, 2, 4, 5})
+	}
+	if n := q.RemoveIf(func(e int) bool { return e%2 == 0 }); n != 2 {
+		t.Errorf("RemoveIf() = %d, want 2", n)
+	}
+	if got := q.ToArray(); !reflect.DeepEqual(got, []int{1, 5}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{1, 5})
+	}
+	q.Put(6)
+	if got := q.ToArray(); !reflect.DeepEqual(got, []int{1, 5, 6}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{1, 5, 6})
+	}
+	it := q.Iterator()
+	for it.HasNext() {
+		if e, _ := it.Next(); e == 5 {
+			if err := it.Remove(); err != nil {
+				t.Errorf("Remove() = %v, want nil", err)
+			}
+		}
+	}
+	if !q.Equals(newArrayList[int](DefaultListConfig, 1, 6)) {
+		t.Errorf("queue = %v, want %v", q, []int{1, 6})
+	}
+	q.Clear()
+	if !q.IsEmpty() || q.RemainingCapacity() != 5 {
+		t.Errorf("Clear() size = %d, want 0", q.Size())
+	}
+}
+
+func Test_arrayBlockingQueue_removeSelf(t *testing.T) {
+	q := NewArrayBlockingQueue[int](5)
+	for _, v := range []int{1, 2, 3, 2} {
+		q.Put(v)
+	}
+	// filter 中访问队列不会死锁
+	if n := q.RemoveIf(func(e int) bool { return q.Contains(e + 1) }); n != 3 {
+		t.Errorf("RemoveIf() = %d, want 3", n)
+	}
+	if got := q.ToArray(); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{3})
+	}
+	if n := q.RemoveAll(q); n != 1 || !q.IsEmpty() {
+		t.Errorf("RemoveAll() = %d, size = %d", n, q.Size())
+	}
+}
+
+func Test_arrayBlockingQueue_ctx(t *testing.T) {
+	q := NewArrayBlockingQueue[int](1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.TakeCtx(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TakeCtx() = %v, want DeadlineExceeded", err)
+	}
+	q.Put(1)
+	if q.Offer(2, 10*time.Millisecond) {
+		t.Error("Offer() = true, want false")
+	}
+	if _, ok := q.Poll(time.Millisecond); !ok {
+		t.Error("Poll() ok = false, want true")
+	}
+	if _, ok := q.Poll(time.Millisecond); ok {
+		t.Error("Poll() ok = true, want false")
+	}
+}
+
+func Test_arrayBlockingQueue_producerConsumer(t *testing.T) {
+	q := NewArrayBlockingQueue[int](4)
+	const n = 1000
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			if err := q.PutCtx(context.Background(), i); err != nil {
+				t.Errorf("PutCtx() = %v, want nil", err)
+			}
+		}
+	}()
+	var sum int
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			e, err := q.TakeCtx(context.Background())
+			if err != nil {
+				t.Errorf("TakeCtx() = %v, want nil", err)
+			}
+			if e != i {
+				t.Errorf("TakeCtx() = %d, want %d", e, i)
+			}
+			sum += e
+		}
+	}()
+	wg.Wait()
+	if sum != n*(n-1)/2 {
+		t.Errorf("sum = %d, want %d", sum, n*(n-1)/2)
+	}
+}
@@ -0,0 +1,402 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+	"math"
+)
+
+// NewArrayDeque 创建一个基于可扩容环形数组的双端队列
+// 参数 initialCapacity 为初始容量，小于1时使用默认值16
+func NewArrayDeque[E comparable](initialCapacity int) Deque[E] {
+	if initialCapacity < 1 {
+		initialCapacity = 16
+	}
+	return &arrayDeque[E]{
+		elements: make([]E, initialCapacity),
+	}
+}
+
+type arrayDeque[E comparable] struct {
+	// elements 环形数组，head 为第一个元素的下标
+	elements   []E
+	head, size int
+	zeroVal    E
+}
+
+func (d *arrayDeque[E]) Size() int {
+	return d.size
+}
+
+func (d *arrayDeque[E]) IsEmpty() bool {
+	return d.size == 0
+}
+
+func (d *arrayDeque[E]) Contains(e E) bool {
+	return d.indexOf(e) >= 0
+}
+
+func (d *arrayDeque[E]) Iterator() Iterator[E] {
+	return &dequeIterator[E]{
+		deque:   d,
+		lastRet: -1,
+	}
+}
+
+func (d *arrayDeque[E]) DescendingIterator() Iterator[E] {
+	return &dequeIterator[E]{
+		deque:      d,
+		cursor:     d.size - 1,
+		lastRet:    -1,
+		descending: true,
+	}
+}
+
+func (d *arrayDeque[E]) ToArray() []E {
+	if d.size == 0 {
+		return nil
+	}
+	res := make([]E, d.size)
+	for i := range res {
+		res[i] = d.elements[d.physical(i)]
+	}
+	return res
+}
+
+func (d *arrayDeque[E]) Add(e E) bool {
+	d.AddLast(e)
+	return true
+}
+
+func (d *arrayDeque[E]) Remove(e E) bool {
+	i := d.indexOf(e)
+	if i < 0 {
+		return false
+	}
+	d.removeAt(i)
+	return true
+}
+
+func (d *arrayDeque[E]) ContainsAll(c Collection[E]) bool {
+	itr := c.Iterator()
+	for itr.HasNext() {
+		if e, err := itr.Next(); err != nil || !d.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *arrayDeque[E]) AddAll(c Collection[E]) {
+	arr := c.ToArray()
+	d.grow(d.size + len(arr))
+	for _, e := range arr {
+		d.elements[d.physical(d.size)] = e
+		d.size++
+	}
+}
+
+func (d *arrayDeque[E]) RemoveAll(c Collection[E]) int {
+	return d.RemoveIf(func(e E) bool {
+		return c.Contains(e)
+	})
+}
+
+func (d *arrayDeque[E]) RemoveIf(filter Predicate[E]) int {
+	var kept int
+	for i := 0; i < d.size; i++ {
+		e := d.elements[d.physical(i)]
+		if !filter(e) {
+			d.elements[d.physical(kept)] = e
+			kept++
+		}
+	}
+	for i := kept; i < d.size; i++ {
+		d.elements[d.physical(i)] = d.zeroVal
+	}
+	cnt := d.size - kept
+	d.size = kept
+	return cnt
+}
+
+func (d *arrayDeque[E]) RetainAll(c Collection[E]) int {
+	return d.RemoveIf(func(e E) bool {
+		return !c.Contains(e)
+	})
+}
+
+func (d *arrayDeque[E]) Clear() {
+	for i := 0; i < d.size; i++ {
+		d.elements[d.physical(i)] = d.zeroVal
+	}
+	d.head, d.size = 0, 0
+}
+
+func (d *arrayDeque[E]) Equals(c Collection[E]) bool {
+	return equals[E](d, c)
+}
+
+func (d *arrayDeque[E]) ForEach(f Consumer[E]) error {
+	for i := 0; i < d.size; i++ {
+		if err := f(d.elements[d.physical(i)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *arrayDeque[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := 0; i < d.size; i++ {
+			if !yield(d.elements[d.physical(i)]) {
+				return
+			}
+		}
+	}
+}
+
+func (d *arrayDeque[E]) GetEqualComparator() constraints.EqualComparator[E] {
+	return comparableEqual[E]()
+}
+
+func (d *arrayDeque[E]) Put(e E) bool {
+	d.AddLast(e)
+	return true
+}
+
+func (d *arrayDeque[E]) Take() (E, bool) {
+	return d.PollFirst()
+}
+
+func (d *arrayDeque[E]) Peek() (E, bool) {
+	return d.PeekFirst()
+}
+
+func (d *arrayDeque[E]) AddFirst(e E) {
+	d.grow(d.size + 1)
+	d.head = d.dec(d.head)
+	d.elements[d.head] = e
+	d.size++
+}
+
+func (d *arrayDeque[E]) AddLast(e E) {
+	d.grow(d.size + 1)
+	d.elements[d.physical(d.size)] = e
+	d.size++
+}
+
+func (d *arrayDeque[E]) PollFirst() (E, bool) {
+	if d.size == 0 {
+		return d.zeroVal, false
+	}
+	e := d.elements[d.head]
+	d.elements[d.head] = d.zeroVal
+	d.head = d.inc(d.head)
+	d.size--
+	return e, true
+}
+
+func (d *arrayDeque[E]) PollLast() (E, bool) {
+	if d.size == 0 {
+		return d.zeroVal, false
+	}
+	i := d.physical(d.size - 1)
+	e := d.elements[i]
+	d.elements[i] = d.zeroVal
+	d.size--
+	return e, true
+}
+
+func (d *arrayDeque[E]) PeekFirst() (E, bool) {
+	if d.size == 0 {
+		return d.zeroVal, false
+	}
+	return d.elements[d.head], true
+}
+
+func (d *arrayDeque[E]) PeekLast() (E, bool) {
+	if d.size == 0 {
+		return d.zeroVal, false
+	}
+	return d.elements[d.physical(d.size-1)], true
+}
+
+func (d *arrayDeque[E]) Push(e E) {
+	d.AddFirst(e)
+}
+
+func (d *arrayDeque[E]) Pop() (E, bool) {
+	return d.PollFirst()
+}
+
+func (d *arrayDeque[E]) String() string {
+	return fmt.Sprintf("%v", d.ToArray())
+}
+
+// removeAt 删除第 i 个元素，移动距离较短一侧的元素
+// 删除后第 i 个元素之前的元素序号不变，之后的元素序号减一
+func (d *arrayDeque[E]) removeAt(i int) {
+	if i < d.size/2 {
+		for j := i; j > 0; j-- {
+			d.elements[d.physical(j)] = d.elements[d.physical(j-1)]
+		}
+		d.elements[d.head] = d.zeroVal
+		d.head = d.inc(d.head)
+	} else {
+		for j := i; j < d.size-1; j++ {
+			d.elements[d.physical(j)] = d.elements[d.physical(j+1)]
+		}
+		d.elements[d.physical(d.size-1)] = d.zeroVal
+	}
+	d.size--
+}
+
+// indexOf 返回元素第一次出现的序号，不存在返回-1
+func (d *arrayDeque[E]) indexOf(e E) int {
+	for i := 0; i < d.size; i++ {
+		if d.elements[d.physical(i)] == e {
+			return i
+		}
+	}
+	return -1
+}
+
+// grow 扩容，保证容量不小于 minCapacity
+func (d *arrayDeque[E]) grow(minCapacity int) {
+	capacity := len(d.elements)
+	if capacity >= minCapacity {
+		return
+	}
+	newCapacity := capacity << 1
+	if newCapacity < minCapacity {
+		newCapacity = minCapacity
+	}
+	tmp := make([]E, newCapacity)
+	n := copy(tmp, d.elements[d.head:])
+	if n < d.size {
+		copy(tmp[n:], d.elements[:d.size-n])
+	}
+	d.elements = tmp
+	d.head = 0
+}
+
+// physical 返回第 i 个元素在环形数组中的下标
+func (d *arrayDeque[E]) physical(i int) int {
+	i += d.head
+	if i >= len(d.elements) {
+		i -= len(d.elements)
+	}
+	return i
+}
+
+func (d *arrayDeque[E]) inc(i int) int {
+	i++
+	if i == len(d.elements) {
+		return 0
+	}
+	return i
+}
+
+func (d *arrayDeque[E]) dec(i int) int {
+	if i == 0 {
+		return len(d.elements) - 1
+	}
+	return i - 1
+}
+
+type dequeIterator[E comparable] struct {
+	// cursor 下一次调用 Next() 方法返回的元素序号
+	// lastRet 上一次调用 Next() 方法返回的元素序号
+	cursor, lastRet int
+
+	descending bool
+	isClose    bool
+	deque      *arrayDeque[E]
+}
+
+func (d *dequeIterator[E]) HasNext() bool {
+	if d.isClose {
+		return false
+	}
+	if d.descending {
+		return d.cursor >= 0
+	}
+	return d.cursor < d.deque.size
+}
+
+func (d *dequeIterator[E]) Next() (e E, err error) {
+	if d.isClose {
+		err = ErrIteratorClose
+		return
+	}
+	if !d.HasNext() {
+		err = ErrNoSuchElement
+		return
+	}
+	i := d.cursor
+	e = d.deque.elements[d.deque.physical(i)]
+	d.lastRet = i
+	if d.descending {
+		d.cursor = i - 1
+	} else {
+		d.cursor = i + 1
+	}
+	return
+}
+
+func (d *dequeIterator[E]) Remove() error {
+	if d.isClose {
+		return ErrIteratorClose
+	}
+	if d.lastRet < 0 {
+		return ErrIllegalState
+	}
+	d.deque.removeAt(d.lastRet)
+	// 逆序迭代时 cursor 在 lastRet 之前，序号不受影响
+	if !d.descending {
+		d.cursor = d.lastRet
+	}
+	d.lastRet = -1
+	return nil
+}
+
+func (d *dequeIterator[E]) ForEachRemaining(action Consumer[E]) error {
+	if d.isClose {
+		return ErrIteratorClose
+	}
+	for d.HasNext() {
+		e, err := d.Next()
+		if err != nil {
+			return err
+		}
+		if err = action(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *dequeIterator[E]) Close() {
+	d.isClose = true
+	d.cursor = math.MaxInt
+	d.lastRet = -1
+}
@@ -0,0 +1,145 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func iteratorValues[E any](t *testing.T, it Iterator[E]) []E {
+	var res []E
+	for it.HasNext() {
+		e, err := it.Next()
+		if err != nil {
+			t.Errorf("Next() = %v, want nil", err)
+			return res
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+func Test_arrayDeque_bothEnds(t *testing.T) {
+	d := NewArrayDeque[int](2)
+	d.AddLast(3)
+	d.AddFirst(2)
+	d.AddFirst(1)
+	d.AddLast(4)
+	d.Put(5)
+	if got := d.ToArray(); !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{1, 2, 3, 4, 5})
+	}
+	if e, ok := d.PeekFirst(); !ok || e != 1 {
+		t.Errorf("PeekFirst() = %v, %v, want 1, true", e, ok)
+	}
+	if e, ok := d.PeekLast(); !ok || e != 5 {
+		t.Errorf("PeekLast() = %v, %v, want 5, true", e, ok)
+	}
+	if e, ok := d.PollLast(); !ok || e != 5 {
+		t.Errorf("PollLast() = %v, %v, want 5, true", e, ok)
+	}
+	if e, ok := d.Take(); !ok || e != 1 {
+		t.Errorf("Take() = %v, %v, want 1, true", e, ok)
+	}
+	if got := iteratorValues(t, d.DescendingIterator()); !reflect.DeepEqual(got, []int{4, 3, 2}) {
+		t.Errorf("DescendingIterator() = %v, want %v", got, []int{4, 3, 2})
+	}
+	d.Clear()
+	if _, ok := d.PollFirst(); ok {
+		t.Error("PollFirst() ok = true, want false")
+	}
+	if _, ok := d.PeekLast(); ok {
+		t.Error("PeekLast() ok = true, want false")
+	}
+}
+
+func Test_arrayDeque_stack(t *testing.T) {
+	d := NewArrayDeque[int](0)
+	for i := 0; i < 100; i++ {
+		d.Push(i)
+	}
+	for i := 99; i >= 0; i-- {
+		if e, ok := d.Pop(); !ok || e != i {
+			t.Errorf("Pop() = %v, %v, want %v, true", e, ok, i)
+		}
+	}
+	if _, ok := d.Pop(); ok {
+		t.Error("Pop() ok = true, want false")
+	}
+}
+
+func Test_arrayDeque_remove(t *testing.T) {
+	d := NewArrayDeque[int](4)
+	d.AddAll(newArrayList[int](DefaultListConfig, 3, 4, 5, 6))
+	d.AddFirst(2)
+	d.AddFirst(1)
+	if !d.Remove(2) || !d.Remove(5) || d.Remove(10) {
+		t.Error("Remove() result mismatch")
+	}
+	if got := d.ToArray(); !reflect.DeepEqual(got, []int{1, 3, 4, 6}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{1, 3, 4, 6})
+	}
+	if n := d.RemoveIf(func(e int) bool { return e > 3 }); n != 2 {
+		t.Errorf("RemoveIf() = %d, want 2", n)
+	}
+	if !d.Equals(newArrayList[int](DefaultListConfig, 1, 3)) {
+		t.Errorf("deque = %v, want %v", d, []int{1, 3})
+	}
+}
+
+func Test_dequeIterator_remove(t *testing.T) {
+	for _, descending := range []bool{false, true} {
+		d := NewArrayDeque[int](4)
+		for _, v := range []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10} {
+			d.AddLast(v)
+		}
+		it := d.Iterator()
+		if descending {
+			it = d.DescendingIterator()
+		}
+		if err := it.Remove(); !errors.Is(err, ErrIllegalState) {
+			t.Errorf("Remove() = %v, want ErrIllegalState", err)
+		}
+		var visited []int
+		for it.HasNext() {
+			v, err := it.Next()
+			if err != nil {
+				t.Errorf("Next() = %v, want nil", err)
+			}
+			visited = append(visited, v)
+			if v%2 == 0 {
+				if err = it.Remove(); err != nil {
+					t.Errorf("Remove() = %v, want nil", err)
+				}
+			}
+		}
+		if len(visited) != 10 {
+			t.Errorf("visited = %v, want 10 elements", visited)
+		}
+		if got := d.ToArray(); !reflect.DeepEqual(got, []int{1, 3, 5, 7, 9}) {
+			t.Errorf("ToArray() = %v, want %v", got, []int{1, 3, 5, 7, 9})
+		}
+		it.Close()
+		if _, err := it.Next(); !errors.Is(err, ErrIteratorClose) {
+			t.Errorf("Next() = %v, want ErrIteratorClose", err)
+		}
+	}
+}
@@ -21,6 +21,7 @@ package collect
 import (
 	"fmt"
 	"github.com/yzrzr/go-util/constraints"
+	"iter"
 	"sort"
 	"strings"
 )
@@ -35,12 +36,24 @@ func NewArrayList[E any](initialCapacity int, comparator constraints.EqualCompar
 	}
 }
 
+// wrapArrayList 使用切片 data 作为底层数组创建 List，不会复制数据
+func wrapArrayList[E comparable](data []E) List[E] {
+	return &arrayList[E]{
+		elementData: data,
+		capacity:    len(data),
+		size:        len(data),
+		comparator:  comparableEqual[E](),
+	}
+}
+
 type arrayList[E any] struct {
 	elementData []E
 	size        int
 	capacity    int
 	zeroVal     E
 	comparator  constraints.EqualComparator[E]
+	// modCount 添加、删除元素的累计次数，迭代器使用它检测并发修改
+	modCount int
 }
 
 func (a *arrayList[E]) Size() int {
@@ -72,6 +85,7 @@ func (a *arrayList[E]) Add(e E) bool {
 	a.grow(a.size + 1)
 	a.elementData[a.size] = e
 	a.size++
+	a.modCount++
 	return true
 }
 
@@ -106,6 +120,7 @@ func (a *arrayList[E]) AddAll(c Collection[E]) {
 	a.grow(num + a.size)
 	copy(a.elementData[a.size:], arr)
 	a.size += num
+	a.modCount++
 }
 
 func (a *arrayList[E]) RemoveAll(c Collection[E]) int {
@@ -119,14 +134,20 @@ func (a *arrayList[E]) RemoveIf(filter Predicate[E]) int {
 }
 
 func (a *arrayList[E]) RemoveIfN(filter Predicate[E], n int) int {
+	return a.removeIfRange(0, a.size, filter, n)
+}
+
+// removeIfRange 删除索引 [fromIndex, toIndex) 范围内最多 n 个满足 filter 的元素，n 为 -1 时不限制个数
+// 先找出所有需要删除的元素再一次性移动剩余的元素，返回删除的元素个数
+func (a *arrayList[E]) removeIfRange(fromIndex, toIndex int, filter Predicate[E], n int) int {
 	if n == 0 {
 		return 0
 	} else if n == -1 {
-		n = a.size
+		n = toIndex - fromIndex
 	}
 	var cnt int
 	indexSlice := make([]int, 0, 2)
-	for i := 0; i < a.size; i++ {
+	for i := fromIndex; i < toIndex; i++ {
 		if filter(a.elementData[i]) {
 			indexSlice = append(indexSlice, i)
 			cnt++
@@ -160,6 +181,7 @@ func (a *arrayList[E]) RemoveIfN(filter Predicate[E], n int) int {
 		a.elementData[i] = a.zeroVal
 	}
 	a.size -= cnt
+	a.modCount++
 	return cnt
 }
 
@@ -174,6 +196,7 @@ func (a *arrayList[E]) Clear() {
 		a.elementData[i] = a.zeroVal
 	}
 	a.size = 0
+	a.modCount++
 }
 
 func (a *arrayList[E]) Equals(c Collection[E]) bool {
@@ -182,7 +205,7 @@ func (a *arrayList[E]) Equals(c Collection[E]) bool {
 
 func (a *arrayList[E]) ForEach(f Consumer[E]) error {
 	var err error
-	for _, v := range a.elementData {
+	for _, v := range a.elementData[:a.size] {
 		err = f(v)
 		if err != nil {
 			return err
@@ -191,6 +214,40 @@ func (a *arrayList[E]) ForEach(f Consumer[E]) error {
 	return nil
 }
 
+func (a *arrayList[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := 0; i < a.size; i++ {
+			if !yield(a.elementData[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (a *arrayList[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := a.size - 1; i >= 0; i-- {
+			// 迭代过程中可能有元素被删除
+			if i >= a.size {
+				continue
+			}
+			if !yield(a.elementData[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (a *arrayList[E]) All2() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		for i := 0; i < a.size; i++ {
+			if !yield(i, a.elementData[i]) {
+				return
+			}
+		}
+	}
+}
+
 func (a *arrayList[E]) ReplaceAll(operator UnaryOperator[E]) {
 	if operator == nil {
 		return
@@ -230,6 +287,7 @@ func (a *arrayList[E]) AddAt(index int, e E) error {
 	copy(a.elementData[index+1:], a.elementData[index:])
 	a.elementData[index] = e
 	a.size++
+	a.modCount++
 	return nil
 }
 
@@ -240,6 +298,7 @@ func (a *arrayList[E]) RemoveAt(index int) (E, error) {
 	}
 	copy(a.elementData[index:], a.elementData[index+1:])
 	a.size--
+	a.modCount++
 	return old, nil
 }
 
@@ -270,14 +329,7 @@ func (a *arrayList[E]) ListIteratorAt(index int) ListIterator[E] {
 }
 
 func (a *arrayList[E]) SubList(fromIndex, toIndex int) List[E] {
-	data := make([]E, toIndex-fromIndex)
-	copy(data, a.elementData[fromIndex:toIndex])
-	return &arrayList[E]{
-		elementData: data,
-		capacity:    cap(data),
-		size:        len(data),
-		comparator:  a.comparator,
-	}
+	return newSubList[E](a, nil, fromIndex, toIndex, a.size)
 }
 
 func (a *arrayList[E]) grow(minCapacity int) {
@@ -306,6 +358,10 @@ func (a *arrayList[E]) grow(minCapacity int) {
 	a.capacity = newCapacity
 }
 
+func (a *arrayList[E]) structuralModCount() int {
+	return a.modCount
+}
+
 func (a *arrayList[E]) rangeCheck(index int) error {
 	if index < 0 || index >= a.size {
 		return fmt.Errorf("index out of range [%d] with length %d", index, a.size)
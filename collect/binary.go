@@ -0,0 +1,377 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// ErrInvalidBinary 二进制数据格式错误、版本不支持或者元素类型不匹配
+var ErrInvalidBinary = errors.New("invalid binary data")
+
+// 二进制格式，所有整数都使用小端序：
+//
+//	version(1 byte) | type(1 byte) | count(uvarint) | payload
+//
+// type 为 binaryGob 时 payload 为 length(uvarint) 加上 gob 编码的元素切片，
+// 否则 payload 为 count 个定长的元素，int、uint、uintptr 固定使用 8 字节
+// 元素的底层类型为内置的整数、浮点数类型时使用定长编码，例如 type UserID int64，其它类型使用 gob 编码
+const binaryVersion byte = 1
+
+const (
+	binaryGob byte = iota
+	binaryInt
+	binaryInt8
+	binaryInt16
+	binaryInt32
+	binaryInt64
+	binaryUint
+	binaryUint8
+	binaryUint16
+	binaryUint32
+	binaryUint64
+	binaryUintptr
+	binaryFloat32
+	binaryFloat64
+)
+
+func marshalBinary[E any](values []E) ([]byte, error) {
+	if buf, ok := marshalFixed(values); ok {
+		return buf, nil
+	}
+	buf := appendBinaryHeader(nil, binaryGob, len(values))
+	if len(values) == 0 {
+		return binary.AppendUvarint(buf, 0), nil
+	}
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(values); err != nil {
+		return nil, err
+	}
+	buf = binary.AppendUvarint(buf, uint64(payload.Len()))
+	return append(buf, payload.Bytes()...), nil
+}
+
+func unmarshalBinary[E any](data []byte) ([]E, error) {
+	if len(data) < 2 {
+		return nil, fmt.Errorf("%w: data too short", ErrInvalidBinary)
+	}
+	if data[0] != binaryVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidBinary, data[0])
+	}
+	typ := data[1]
+	n, k := binary.Uvarint(data[2:])
+	if k <= 0 {
+		return nil, fmt.Errorf("%w: bad element count", ErrInvalidBinary)
+	}
+	data = data[2+k:]
+	if typ != binaryGob {
+		return unmarshalFixed[E](typ, n, data)
+	}
+	size, k := binary.Uvarint(data)
+	if k <= 0 || size != uint64(len(data)-k) {
+		return nil, fmt.Errorf("%w: bad payload length", ErrInvalidBinary)
+	}
+	var values []E
+	if size > 0 {
+		if err := gob.NewDecoder(bytes.NewReader(data[k:])).Decode(&values); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBinary, err)
+		}
+	}
+	if uint64(len(values)) != n {
+		return nil, fmt.Errorf("%w: element count mismatch", ErrInvalidBinary)
+	}
+	return values, nil
+}
+
+func appendBinaryHeader(buf []byte, typ byte, n int) []byte {
+	buf = append(buf, binaryVersion, typ)
+	return binary.AppendUvarint(buf, uint64(n))
+}
+
+// marshalFixed 底层类型为内置的整数、浮点数类型的元素使用定长编码，其它类型返回 false
+func marshalFixed[E any](values []E) ([]byte, bool) {
+	le := binary.LittleEndian
+	switch reflect.TypeFor[E]().Kind() {
+	case reflect.Int:
+		return encodeFixed(binaryInt, 8, asSlice[int](values), func(b []byte, v int) { le.PutUint64(b, uint64(v)) }), true
+	case reflect.Int8:
+		return encodeFixed(binaryInt8, 1, asSlice[int8](values), func(b []byte, v int8) { b[0] = byte(v) }), true
+	case reflect.Int16:
+		return encodeFixed(binaryInt16, 2, asSlice[int16](values), func(b []byte, v int16) { le.PutUint16(b, uint16(v)) }), true
+	case reflect.Int32:
+		return encodeFixed(binaryInt32, 4, asSlice[int32](values), func(b []byte, v int32) { le.PutUint32(b, uint32(v)) }), true
+	case reflect.Int64:
+		return encodeFixed(binaryInt64, 8, asSlice[int64](values), func(b []byte, v int64) { le.PutUint64(b, uint64(v)) }), true
+	case reflect.Uint:
+		return encodeFixed(binaryUint, 8, asSlice[uint](values), func(b []byte, v uint) { le.PutUint64(b, uint64(v)) }), true
+	case reflect.Uint8:
+		return encodeFixed(binaryUint8, 1, asSlice[uint8](values), func(b []byte, v uint8) { b[0] = v }), true
+	case reflect.Uint16:
+		return encodeFixed(binaryUint16, 2, asSlice[uint16](values), func(b []byte, v uint16) { le.PutUint16(b, v) }), true
+	case reflect.Uint32:
+		return encodeFixed(binaryUint32, 4, asSlice[uint32](values), func(b []byte, v uint32) { le.PutUint32(b, v) }), true
+	case reflect.Uint64:
+		return encodeFixed(binaryUint64, 8, asSlice[uint64](values), func(b []byte, v uint64) { le.PutUint64(b, v) }), true
+	case reflect.Uintptr:
+		return encodeFixed(binaryUintptr, 8, asSlice[uintptr](values), func(b []byte, v uintptr) { le.PutUint64(b, uint64(v)) }), true
+	case reflect.Float32:
+		return encodeFixed(binaryFloat32, 4, asSlice[float32](values), func(b []byte, v float32) { le.PutUint32(b, math.Float32bits(v)) }), true
+	case reflect.Float64:
+		return encodeFixed(binaryFloat64, 8, asSlice[float64](values), func(b []byte, v float64) { le.PutUint64(b, math.Float64bits(v)) }), true
+	}
+	return nil, false
+}
+
+func unmarshalFixed[E any](typ byte, n uint64, data []byte) ([]E, error) {
+	le := binary.LittleEndian
+	switch reflect.TypeFor[E]().Kind() {
+	case reflect.Int:
+		values, err := decodeFixed(typ, binaryInt, 8, n, data, func(b []byte) int { return int(le.Uint64(b)) })
+		return asSlice[E](values), err
+	case reflect.Int8:
+		values, err := decodeFixed(typ, binaryInt8, 1, n, data, func(b []byte) int8 { return int8(b[0]) })
+		return asSlice[E](values), err
+	case reflect.Int16:
+		values, err := decodeFixed(typ, binaryInt16, 2, n, data, func(b []byte) int16 { return int16(le.Uint16(b)) })
+		return asSlice[E](values), err
+	case reflect.Int32:
+		values, err := decodeFixed(typ, binaryInt32, 4, n, data, func(b []byte) int32 { return int32(le.Uint32(b)) })
+		return asSlice[E](values), err
+	case reflect.Int64:
+		values, err := decodeFixed(typ, binaryInt64, 8, n, data, func(b []byte) int64 { return int64(le.Uint64(b)) })
+		return asSlice[E](values), err
+	case reflect.Uint:
+		values, err := decodeFixed(typ, binaryUint, 8, n, data, func(b []byte) uint { return uint(le.Uint64(b)) })
+		return asSlice[E](values), err
+	case reflect.Uint8:
+		values, err := decodeFixed(typ, binaryUint8, 1, n, data, func(b []byte) uint8 { return b[0] })
+		return asSlice[E](values), err
+	case reflect.Uint16:
+		values, err := decodeFixed(typ, binaryUint16, 2, n, data, le.Uint16)
+		return asSlice[E](values), err
+	case reflect.Uint32:
+		values, err := decodeFixed(typ, binaryUint32, 4, n, data, le.Uint32)
+		return asSlice[E](values), err
+	case reflect.Uint64:
+		values, err := decodeFixed(typ, binaryUint64, 8, n, data, le.Uint64)
+		return asSlice[E](values), err
+	case reflect.Uintptr:
+		values, err := decodeFixed(typ, binaryUintptr, 8, n, data, func(b []byte) uintptr { return uintptr(le.Uint64(b)) })
+		return asSlice[E](values), err
+	case reflect.Float32:
+		values, err := decodeFixed(typ, binaryFloat32, 4, n, data, func(b []byte) float32 { return math.Float32frombits(le.Uint32(b)) })
+		return asSlice[E](values), err
+	case reflect.Float64:
+		values, err := decodeFixed(typ, binaryFloat64, 8, n, data, func(b []byte) float64 { return math.Float64frombits(le.Uint64(b)) })
+		return asSlice[E](values), err
+	}
+	return nil, fmt.Errorf("%w: element type mismatch", ErrInvalidBinary)
+}
+
+// asSlice 将切片重新解释为 []T，不复制数据，调用方需要保证 S 的底层类型为 T
+func asSlice[T, S any](values []S) []T {
+	if values == nil {
+		return nil
+	}
+	return unsafe.Slice((*T)(unsafe.Pointer(unsafe.SliceData(values))), len(values))
+}
+
+func encodeFixed[T any](typ byte, width int, values []T, put func(b []byte, v T)) []byte {
+	buf := appendBinaryHeader(make([]byte, 0, 2+binary.MaxVarintLen64+width*len(values)), typ, len(values))
+	off := len(buf)
+	buf = buf[:off+width*len(values)]
+	for i, v := range values {
+		put(buf[off+i*width:], v)
+	}
+	return buf
+}
+
+func decodeFixed[T any](typ, want byte, width int, n uint64, data []byte, get func(b []byte) T) ([]T, error) {
+	if typ != want {
+		return nil, fmt.Errorf("%w: element type mismatch", ErrInvalidBinary)
+	}
+	if n != uint64(len(data)/width) || len(data)%width != 0 {
+		return nil, fmt.Errorf("%w: bad payload length", ErrInvalidBinary)
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	values := make([]T, n)
+	for i := range values {
+		values[i] = get(data[i*width:])
+	}
+	return values, nil
+}
+
+func (a *arrayList[E]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(a.elementData[:a.size])
+}
+
+func (a *arrayList[E]) UnmarshalBinary(data []byte) error {
+	values, err := unmarshalBinary[E](data)
+	if err != nil {
+		return err
+	}
+	a.Clear()
+	a.grow(len(values))
+	copy(a.elementData, values)
+	a.size = len(values)
+	return nil
+}
+
+func (a *arrayList[E]) GobEncode() ([]byte, error) {
+	return a.MarshalBinary()
+}
+
+func (a *arrayList[E]) GobDecode(data []byte) error {
+	return a.UnmarshalBinary(data)
+}
+
+func (l *linkedList[E]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(l.ToArray())
+}
+
+func (l *linkedList[E]) UnmarshalBinary(data []byte) error {
+	values, err := unmarshalBinary[E](data)
+	if err != nil {
+		return err
+	}
+	resetCollection[E](l, values)
+	return nil
+}
+
+func (l *linkedList[E]) GobEncode() ([]byte, error) {
+	return l.MarshalBinary()
+}
+
+func (l *linkedList[E]) GobDecode(data []byte) error {
+	return l.UnmarshalBinary(data)
+}
+
+func (s *subList[E]) MarshalBinary() ([]byte, error) {
+	values, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return marshalBinary(values)
+}
+
+func (s *subList[E]) UnmarshalBinary(data []byte) error {
+	if err := s.checkForComodification(); err != nil {
+		return err
+	}
+	values, err := unmarshalBinary[E](data)
+	if err != nil {
+		return err
+	}
+	resetCollection[E](s, values)
+	return nil
+}
+
+func (s *subList[E]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *subList[E]) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+func (h *hashSet[E]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(h.ToArray())
+}
+
+func (h *hashSet[E]) UnmarshalBinary(data []byte) error {
+	values, err := unmarshalBinary[E](data)
+	if err != nil {
+		return err
+	}
+	resetCollection[E](h, values)
+	return nil
+}
+
+func (h *hashSet[E]) GobEncode() ([]byte, error) {
+	return h.MarshalBinary()
+}
+
+func (h *hashSet[E]) GobDecode(data []byte) error {
+	return h.UnmarshalBinary(data)
+}
+
+func (a *safeList[E]) MarshalBinary() ([]byte, error) {
+	a.RLock()
+	defer a.RUnlock()
+	if err := checkView(a.List); err != nil {
+		return nil, err
+	}
+	return marshalBinary(a.List.ToArray())
+}
+
+func (a *safeList[E]) UnmarshalBinary(data []byte) error {
+	values, err := unmarshalBinary[E](data)
+	if err != nil {
+		return err
+	}
+	a.Lock()
+	defer a.Unlock()
+	if err := checkView(a.List); err != nil {
+		return err
+	}
+	resetCollection[E](a.List, values)
+	return nil
+}
+
+func (a *safeList[E]) GobEncode() ([]byte, error) {
+	return a.MarshalBinary()
+}
+
+func (a *safeList[E]) GobDecode(data []byte) error {
+	return a.UnmarshalBinary(data)
+}
+
+func (a *safeSet[E]) MarshalBinary() ([]byte, error) {
+	a.RLock()
+	defer a.RUnlock()
+	return marshalBinary(a.Set.ToArray())
+}
+
+func (a *safeSet[E]) UnmarshalBinary(data []byte) error {
+	values, err := unmarshalBinary[E](data)
+	if err != nil {
+		return err
+	}
+	a.Lock()
+	defer a.Unlock()
+	resetCollection[E](a.Set, values)
+	return nil
+}
+
+func (a *safeSet[E]) GobEncode() ([]byte, error) {
+	return a.MarshalBinary()
+}
+
+func (a *safeSet[E]) GobDecode(data []byte) error {
+	return a.UnmarshalBinary(data)
+}
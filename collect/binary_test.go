@@ -0,0 +1,136 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_binary_fixed(t *testing.T) {
+	for _, c := range configList {
+		list := NewList[int64](c)
+		for i := int64(-500); i < 500; i++ {
+			list.Add(i * 1e12)
+		}
+		data, err := list.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary() = %v, want nil", err)
+		}
+		if len(data) != 2+2+8*1000 {
+			t.Errorf("len(MarshalBinary()) = %v, want %v", len(data), 2+2+8*1000)
+		}
+		got := NewList[int64](c)
+		got.Add(1)
+		if err := got.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil || !got.Equals(list) {
+			t.Errorf("UnmarshalBinary() = %v, %v, want %v", err, got.Size(), list.Size())
+		}
+	}
+	set := NewSet[float32]()
+	set.AddAll(wrapArrayList([]float32{1.5, -2, 3.25}))
+	data, _ := set.(encoding.BinaryMarshaler).MarshalBinary()
+	got := NewSet[float32]()
+	if err := got.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil || !got.Equals(set) {
+		t.Errorf("UnmarshalBinary() = %v, %v, want %v", err, got, set)
+	}
+	// 元素类型不匹配
+	if err := NewSet[float64]().(encoding.BinaryUnmarshaler).UnmarshalBinary(data); !errors.Is(err, ErrInvalidBinary) {
+		t.Errorf("UnmarshalBinary() = %v, want ErrInvalidBinary", err)
+	}
+}
+
+func Test_binary_namedFixed(t *testing.T) {
+	type UserID int64
+	list := NewList[UserID](DefaultListConfig)
+	for i := UserID(1); i <= 100; i++ {
+		list.Add(i * 1e9)
+	}
+	data, err := list.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() = %v, want nil", err)
+	}
+	// 基于 int64 定义的新类型同样使用定长编码
+	if data[1] != binaryInt64 || len(data) != 2+1+8*100 {
+		t.Errorf("MarshalBinary() type = %d, len = %d", data[1], len(data))
+	}
+	got := NewList[UserID](DefaultListConfig)
+	if err := got.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil || !got.Equals(list) {
+		t.Errorf("UnmarshalBinary() = %v, %v", err, got)
+	}
+	// 与底层类型的编码兼容
+	ids := NewList[int64](DefaultListConfig)
+	if err := ids.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil || ids.Size() != 100 {
+		t.Errorf("UnmarshalBinary() = %v, size = %d", err, ids.Size())
+	}
+}
+
+func Test_binary_gob(t *testing.T) {
+	for _, c := range configList {
+		list := newArrayList[TestStruct](c, TestStruct{Id: 1, Data: []int{1, 2}}, TestStruct{Id: 2})
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(list); err != nil {
+			t.Fatalf("Encode() = %v, want nil", err)
+		}
+		got := NewList[TestStruct](c)
+		if err := gob.NewDecoder(&buf).Decode(got); err != nil {
+			t.Fatalf("Decode() = %v, want nil", err)
+		}
+		if !reflect.DeepEqual(got.ToArray(), []TestStruct{{Id: 1, Data: []int{1, 2}}, {Id: 2}}) {
+			t.Errorf("Decode() = %v, want %v", got, list)
+		}
+	}
+	set := NewSafeSet(SetOf("a", "b"))
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(set); err != nil {
+		t.Fatalf("Encode() = %v, want nil", err)
+	}
+	got := NewSafeSet(NewSet[string]())
+	if err := gob.NewDecoder(&buf).Decode(got); err != nil || !got.Equals(SetOf("a", "b")) {
+		t.Errorf("Decode() = %v, %v, want %v", err, got, set)
+	}
+}
+
+func Test_binary_invalid(t *testing.T) {
+	list := newArrayList[string](DefaultListConfig, "a")
+	data, _ := list.(encoding.BinaryMarshaler).MarshalBinary()
+	empty, _ := NewList[string](DefaultListConfig).(encoding.BinaryMarshaler).MarshalBinary()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"short", data[:1]},
+		{"version", append([]byte{99}, data[1:]...)},
+		{"truncated", data[:len(data)-1]},
+		{"type", []byte{binaryVersion, binaryInt, 1, 0, 0, 0, 0, 0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		err := NewList[string](DefaultListConfig).(encoding.BinaryUnmarshaler).UnmarshalBinary(tt.data)
+		if !errors.Is(err, ErrInvalidBinary) {
+			t.Errorf("%s UnmarshalBinary() = %v, want ErrInvalidBinary", tt.name, err)
+		}
+	}
+	got := newArrayList[string](DefaultListConfig, "x")
+	if err := got.(encoding.BinaryUnmarshaler).UnmarshalBinary(empty); err != nil || !got.IsEmpty() {
+		t.Errorf("UnmarshalBinary() = %v, %v, want nil, []", err, got)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock 时钟接口，用于获取当前时间和创建定时器
+// 测试时可以注入 ManualClock 手动推进时间，不需要真正等待
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+
+	// NewTimer 创建一个在 d 时长后触发的定时器
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer 定时器接口
+type Timer interface {
+	// C 返回定时器触发时写入当前时间的通道
+	C() <-chan time.Time
+
+	// Stop 停止定时器，如果定时器已经触发或已经停止返回false
+	Stop() bool
+}
+
+// deadlineClock 可以按照到期时间创建定时器的时钟，读取当前时间和创建定时器之间时钟不会前进
+type deadlineClock interface {
+	newTimerAt(deadline time.Time) Timer
+}
+
+// newTimerAt 创建在 deadline 触发的定时器，clock 没有实现 deadlineClock 时按照剩余时长创建
+func newTimerAt(clock Clock, deadline time.Time) Timer {
+	if c, ok := clock.(deadlineClock); ok {
+		return c.newTimerAt(deadline)
+	}
+	return clock.NewTimer(deadline.Sub(clock.Now()))
+}
+
+// SystemClock 使用系统时间的时钟
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// NewManualClock 创建一个手动控制的时钟，初始时间为 now
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// ManualClock 手动控制的时钟，只有调用 Advance 时时间才会前进，并触发到期的定时器
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.addTimer(c.now.Add(d))
+}
+
+func (c *ManualClock) newTimerAt(deadline time.Time) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.addTimer(deadline)
+}
+
+// addTimer 创建在 deadline 触发的定时器，deadline 已经到达时立即触发，调用时需要持有 mu
+func (c *ManualClock) addTimer(deadline time.Time) *manualTimer {
+	t := &manualTimer{
+		clock:    c,
+		deadline: deadline,
+		c:        make(chan time.Time, 1),
+	}
+	if deadline.After(c.now) {
+		c.timers = append(c.timers, t)
+	} else {
+		t.c <- c.now
+	}
+	return t
+}
+
+// Advance 将时钟向前推进 d 时长，并触发所有到期的定时器
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.deadline.After(c.now) {
+			pending = append(pending, t)
+		} else {
+			t.c <- c.now
+		}
+	}
+	clear(c.timers[len(pending):])
+	c.timers = pending
+}
+
+// PendingTimers 返回尚未触发且未停止的定时器个数
+func (c *ManualClock) PendingTimers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+type manualTimer struct {
+	clock    *ManualClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, v := range t.clock.timers {
+		if v == t {
+			t.clock.timers = slices.Delete(t.clock.timers, i, i+1)
+			return true
+		}
+	}
+	return false
+}
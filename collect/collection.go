@@ -18,7 +18,11 @@
 
 package collect
 
-import "github.com/yzrzr/go-util/constraints"
+import (
+	"errors"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+)
 
 // Collection 集合的根接口
 type Collection[E any] interface {
@@ -72,6 +76,10 @@ type Collection[E any] interface {
 	// ForEach 迭代集合中的元素，直到所有元素都被处理或返回错误
 	ForEach(f Consumer[E]) error
 
+	// All 返回迭代集合中元素的 iter.Seq，迭代顺序与 Iterator 一致，可以直接用于 for range 循环
+	// for e := range c.All() {}
+	All() iter.Seq[E]
+
 	// GetEqualComparator 返回元素比较器
 	GetEqualComparator() constraints.EqualComparator[E]
 }
@@ -81,3 +89,55 @@ type AnyEqualComparableFunc[E any] func(v1, v2 E) bool
 func (f AnyEqualComparableFunc[E]) Equal(v1, v2 E) bool {
 	return f(v1, v2)
 }
+
+// comparableEqual 返回使用 == 比较元素的比较器
+func comparableEqual[E comparable]() constraints.EqualComparator[E] {
+	return equalityFunc[E](func(v1, v2 E) bool {
+		return v1 == v2
+	})
+}
+
+// defaultEqualComparator 返回使用 DefaultEqualFunc 比较元素的比较器
+func defaultEqualComparator[E any]() constraints.EqualComparator[E] {
+	comparator := DefaultEqualFunc()
+	return equalityFunc[E](func(v1, v2 E) bool {
+		return comparator.Equal(v1, v2)
+	})
+}
+
+// orDefaultComparator comparator 为 nil 时返回 defaultEqualComparator，例如 Set 的 GetEqualComparator 返回 nil
+func orDefaultComparator[E any](comparator constraints.EqualComparator[E]) constraints.EqualComparator[E] {
+	if comparator == nil {
+		return defaultEqualComparator[E]()
+	}
+	return comparator
+}
+
+// equalityComparator 对可比较的元素与 == 语义一致的比较器，这些元素可以直接使用 map 查找
+type equalityComparator interface {
+	usesEquality()
+}
+
+// equalityFunc comparableEqual 和 defaultEqualComparator 使用的比较函数
+type equalityFunc[E any] func(v1, v2 E) bool
+
+func (f equalityFunc[E]) Equal(v1, v2 E) bool {
+	return f(v1, v2)
+}
+
+func (f equalityFunc[E]) usesEquality() {}
+
+// errStopIteration forEachSeq 中用于提前结束 ForEach 的错误
+var errStopIteration = errors.New("stop iteration")
+
+// forEachSeq 使用 ForEach 方法创建 iter.Seq，yield 返回 false 时结束 ForEach
+func forEachSeq[E any](forEach func(f Consumer[E]) error) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		_ = forEach(func(e E) error {
+			if !yield(e) {
+				return errStopIteration
+			}
+			return nil
+		})
+	}
+}
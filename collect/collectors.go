@@ -0,0 +1,140 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"github.com/yzrzr/go-util/constraints"
+	"strings"
+)
+
+// GroupingBy 使用 keyFn 计算集合 c 中每个元素的键，按照键对元素分组
+// 每个分组中元素的顺序与 c 的迭代顺序一致，分组 List 使用 c 的元素比较器，c 没有比较器时使用 DefaultEqualFunc
+func GroupingBy[E any, K comparable](c Collection[E], keyFn func(e E) K) map[K]List[E] {
+	res := make(map[K]List[E])
+	comparator := orDefaultComparator(c.GetEqualComparator())
+	for e := range c.All() {
+		k := keyFn(e)
+		group, ok := res[k]
+		if !ok {
+			group = NewArrayList[E](16, comparator)
+			res[k] = group
+		}
+		group.Add(e)
+	}
+	return res
+}
+
+// PartitioningBy 按照 predicate 的结果将集合 c 中的元素分为两组
+// 返回值总是同时包含 true 和 false 两个键，没有元素的分组为空 List
+func PartitioningBy[E any](c Collection[E], predicate Predicate[E]) map[bool]List[E] {
+	comparator := orDefaultComparator(c.GetEqualComparator())
+	res := map[bool]List[E]{
+		true:  NewArrayList[E](16, comparator),
+		false: NewArrayList[E](16, comparator),
+	}
+	for e := range c.All() {
+		res[predicate(e)].Add(e)
+	}
+	return res
+}
+
+// ToMap 使用 keyFn、valueFn 将集合 c 中的元素转换为键值对
+// 多个元素的键相同时使用 merge 合并旧值和新值，merge 为 nil 时保留新值
+func ToMap[E any, K comparable, V any](c Collection[E], keyFn func(e E) K, valueFn func(e E) V, merge func(old, v V) V) map[K]V {
+	res := make(map[K]V, c.Size())
+	for e := range c.All() {
+		k, v := keyFn(e), valueFn(e)
+		if old, ok := res[k]; ok && merge != nil {
+			v = merge(old, v)
+		}
+		res[k] = v
+	}
+	return res
+}
+
+// Joining 按照迭代顺序使用 sep 连接集合 c 中的字符串
+func Joining[E ~string](c Collection[E], sep string) string {
+	build := strings.Builder{}
+	first := true
+	for e := range c.All() {
+		if !first {
+			build.WriteString(sep)
+		}
+		build.WriteString(string(e))
+		first = false
+	}
+	return build.String()
+}
+
+// MinOf 返回集合 c 中最小的元素，集合为空时第二个返回值为 false
+func MinOf[E constraints.Ordered](c Collection[E]) (E, bool) {
+	var res E
+	var ok bool
+	for e := range c.All() {
+		if !ok || e < res {
+			res, ok = e, true
+		}
+	}
+	return res, ok
+}
+
+// MaxOf 返回集合 c 中最大的元素，集合为空时第二个返回值为 false
+func MaxOf[E constraints.Ordered](c Collection[E]) (E, bool) {
+	var res E
+	var ok bool
+	for e := range c.All() {
+		if !ok || e > res {
+			res, ok = e, true
+		}
+	}
+	return res, ok
+}
+
+// SummaryStatistics 数值集合的统计信息，Count 为 0 时其余字段都是零值
+type SummaryStatistics[E constraints.Number] struct {
+	Count int
+	Sum   E
+	Min   E
+	Max   E
+}
+
+// Average 返回平均值，Count 为 0 时返回 0
+func (s SummaryStatistics[E]) Average() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Sum) / float64(s.Count)
+}
+
+// Summarizing 一次迭代计算数值集合 c 的个数、总和、最小值和最大值
+// 总和使用 E 类型累加，整数类型需要注意溢出
+func Summarizing[E constraints.Number](c Collection[E]) SummaryStatistics[E] {
+	var s SummaryStatistics[E]
+	for e := range c.All() {
+		if s.Count == 0 || e < s.Min {
+			s.Min = e
+		}
+		if s.Count == 0 || e > s.Max {
+			s.Max = e
+		}
+		s.Sum += e
+		s.Count++
+	}
+	return s
+}
@@ -0,0 +1,116 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_GroupingBy(t *testing.T) {
+	list := newArrayList[string](DefaultListConfig, "apple", "avocado", "banana", "blueberry", "cherry")
+	groups := GroupingBy[string](list, func(e string) byte {
+		return e[0]
+	})
+	if len(groups) != 3 {
+		t.Fatalf("len(GroupingBy()) = %v, want 3", len(groups))
+	}
+	if got := groups['b'].ToArray(); !reflect.DeepEqual(got, []string{"banana", "blueberry"}) {
+		t.Errorf("GroupingBy()['b'] = %v, want %v", got, []string{"banana", "blueberry"})
+	}
+	var cnt int
+	_ = groups['c'].ForEach(func(e string) error {
+		cnt++
+		return nil
+	})
+	if cnt != 1 {
+		t.Errorf("ForEach() visited %d elements, want 1", cnt)
+	}
+}
+
+func Test_PartitioningBy(t *testing.T) {
+	parts := PartitioningBy[int](SetOf(1, 3, 5), func(e int) bool {
+		return e%2 == 0
+	})
+	if !parts[true].IsEmpty() || parts[false].Size() != 3 {
+		t.Errorf("PartitioningBy() = %v, want map[false:[1 3 5] true:[]]", parts)
+	}
+	// Set 没有比较器，分组 List 使用默认的比较器
+	if !parts[false].Contains(3) || parts[true].Contains(3) {
+		t.Errorf("PartitioningBy() Contains(3) = %v", parts[false].Contains(3))
+	}
+	groups := GroupingBy[int](SetOf(1, 2, 3), func(e int) bool {
+		return e > 1
+	})
+	if !groups[true].Contains(2) || groups[false].Contains(2) {
+		t.Errorf("GroupingBy() Contains(2) = %v", groups[true].Contains(2))
+	}
+}
+
+func Test_ToMap(t *testing.T) {
+	list := newArrayList[string](DefaultListConfig, "a", "bb", "cc", "ddd")
+	got := ToMap[string](list, func(e string) int {
+		return len(e)
+	}, func(e string) string {
+		return e
+	}, func(old, v string) string {
+		return old + "," + v
+	})
+	if want := map[int]string{1: "a", 2: "bb,cc", 3: "ddd"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToMap() = %v, want %v", got, want)
+	}
+	got = ToMap[string](list, func(e string) int {
+		return len(e)
+	}, func(e string) string {
+		return e
+	}, nil)
+	if got[2] != "cc" {
+		t.Errorf("ToMap()[2] = %v, want cc", got[2])
+	}
+}
+
+func Test_Joining(t *testing.T) {
+	if got := Joining[string](newArrayList[string](DefaultListConfig, "a", "b", "c"), ", "); got != "a, b, c" {
+		t.Errorf("Joining() = %v, want a, b, c", got)
+	}
+	if got := Joining[string](NewLinkedHashSet[string](), ","); got != "" {
+		t.Errorf("Joining() = %v, want empty", got)
+	}
+}
+
+func Test_Summarizing(t *testing.T) {
+	list := newArrayList[int](DefaultListConfig, 4, -2, 9, 1)
+	s := Summarizing[int](list)
+	want := SummaryStatistics[int]{Count: 4, Sum: 12, Min: -2, Max: 9}
+	if s != want || s.Average() != 3 {
+		t.Errorf("Summarizing() = %+v, %v, want %+v, 3", s, s.Average(), want)
+	}
+	if s := Summarizing[float64](NewLinkedHashSet[float64]()); s.Count != 0 || s.Average() != 0 {
+		t.Errorf("Summarizing() = %+v, want zero", s)
+	}
+	if e, ok := MinOf[string](SetOf("b", "a", "c")); !ok || e != "a" {
+		t.Errorf("MinOf() = %v, %v, want a, true", e, ok)
+	}
+	if e, ok := MaxOf[int](list); !ok || e != 9 {
+		t.Errorf("MaxOf() = %v, %v, want 9, true", e, ok)
+	}
+	if _, ok := MaxOf[int](NewLinkedHashSet[int]()); ok {
+		t.Error("MaxOf() on empty collection = true, want false")
+	}
+}
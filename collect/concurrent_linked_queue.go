@@ -0,0 +1,243 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+	"sync/atomic"
+)
+
+// NewConcurrentLinkedQueue 创建一个基于链表的无锁并发队列，容量不受限制
+// 基于 Michael-Scott 算法实现，Put、Take、Peek 只使用 CAS 操作，不会获取任何锁
+// Size 需要遍历整个队列，时间复杂度为 O(n)，并发修改时结果只是一个近似值
+// Iterator、ForEach、ToArray 等遍历操作都是弱一致的，不会阻塞其它操作
+func NewConcurrentLinkedQueue[E comparable]() Queue[E] {
+	q := &concurrentLinkedQueue[E]{}
+	dummy := &concurrentQueueNode[E]{}
+	q.head.Store(dummy)
+	q.tail.Store(dummy)
+	return q
+}
+
+// concurrentQueueNode item 为 nil 表示节点中的元素已经被取走或删除
+type concurrentQueueNode[E any] struct {
+	item atomic.Pointer[E]
+	next atomic.Pointer[concurrentQueueNode[E]]
+}
+
+type concurrentLinkedQueue[E comparable] struct {
+	// head 哨兵节点，head.next 之后为队列中的元素
+	// tail 指向最后一个节点或者倒数第二个节点，落后的 tail 由后续操作推进
+	head, tail atomic.Pointer[concurrentQueueNode[E]]
+	zeroVal    E
+}
+
+func (q *concurrentLinkedQueue[E]) Size() int {
+	var size int
+	q.each(func(p *concurrentQueueNode[E], e *E) bool {
+		size++
+		return true
+	})
+	return size
+}
+
+func (q *concurrentLinkedQueue[E]) IsEmpty() bool {
+	_, ok := q.Peek()
+	return !ok
+}
+
+func (q *concurrentLinkedQueue[E]) Contains(e E) bool {
+	var found bool
+	q.each(func(p *concurrentQueueNode[E], v *E) bool {
+		found = *v == e
+		return !found
+	})
+	return found
+}
+
+func (q *concurrentLinkedQueue[E]) Iterator() Iterator[E] {
+	return newSnapshotIterator[E](q)
+}
+
+func (q *concurrentLinkedQueue[E]) ToArray() []E {
+	var res []E
+	q.each(func(p *concurrentQueueNode[E], e *E) bool {
+		res = append(res, *e)
+		return true
+	})
+	return res
+}
+
+func (q *concurrentLinkedQueue[E]) Add(e E) bool {
+	return q.Put(e)
+}
+
+// Remove 删除队列中第一个等于 e 的元素
+// 元素只会被标记为已删除，所在的节点在 Take 经过时才会从链表中移除
+func (q *concurrentLinkedQueue[E]) Remove(e E) bool {
+	var removed bool
+	q.each(func(p *concurrentQueueNode[E], v *E) bool {
+		removed = *v == e && p.item.CompareAndSwap(v, nil)
+		return !removed
+	})
+	return removed
+}
+
+func (q *concurrentLinkedQueue[E]) ContainsAll(c Collection[E]) bool {
+	itr := c.Iterator()
+	for itr.HasNext() {
+		if e, err := itr.Next(); err != nil || !q.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (q *concurrentLinkedQueue[E]) AddAll(c Collection[E]) {
+	for _, e := range c.ToArray() {
+		q.Put(e)
+	}
+}
+
+func (q *concurrentLinkedQueue[E]) RemoveAll(c Collection[E]) int {
+	return q.RemoveIf(func(e E) bool {
+		return c.Contains(e)
+	})
+}
+
+func (q *concurrentLinkedQueue[E]) RemoveIf(filter Predicate[E]) int {
+	var cnt int
+	q.each(func(p *concurrentQueueNode[E], e *E) bool {
+		if filter(*e) && p.item.CompareAndSwap(e, nil) {
+			cnt++
+		}
+		return true
+	})
+	return cnt
+}
+
+func (q *concurrentLinkedQueue[E]) RetainAll(c Collection[E]) int {
+	return q.RemoveIf(func(e E) bool {
+		return !c.Contains(e)
+	})
+}
+
+// Clear 依次取出队列中的元素，与 Clear 并发插入的元素可能会被保留
+func (q *concurrentLinkedQueue[E]) Clear() {
+	for {
+		if _, ok := q.Take(); !ok {
+			return
+		}
+	}
+}
+
+func (q *concurrentLinkedQueue[E]) Equals(c Collection[E]) bool {
+	return equals[E](q, c)
+}
+
+func (q *concurrentLinkedQueue[E]) ForEach(f Consumer[E]) error {
+	var err error
+	q.each(func(p *concurrentQueueNode[E], e *E) bool {
+		err = f(*e)
+		return err == nil
+	})
+	return err
+}
+
+func (q *concurrentLinkedQueue[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		q.each(func(p *concurrentQueueNode[E], e *E) bool {
+			return yield(*e)
+		})
+	}
+}
+
+func (q *concurrentLinkedQueue[E]) GetEqualComparator() constraints.EqualComparator[E] {
+	return comparableEqual[E]()
+}
+
+// Put 将元素插入队列尾部，队列容量不受限制，总是返回 true
+func (q *concurrentLinkedQueue[E]) Put(e E) bool {
+	node := &concurrentQueueNode[E]{}
+	node.item.Store(&e)
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+		if tail != q.tail.Load() {
+			continue
+		}
+		if next != nil {
+			// tail 落后了，帮助推进后重试
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, node) {
+			q.tail.CompareAndSwap(tail, node)
+			return true
+		}
+	}
+}
+
+func (q *concurrentLinkedQueue[E]) Take() (E, bool) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		first := head.next.Load()
+		if head != q.head.Load() {
+			continue
+		}
+		if first == nil {
+			return q.zeroVal, false
+		}
+		if head == tail {
+			q.tail.CompareAndSwap(tail, first)
+			continue
+		}
+		if q.head.CompareAndSwap(head, first) {
+			// first 成为新的哨兵节点，取走其中的元素，元素已被 Remove 删除时继续取下一个
+			if e := first.item.Swap(nil); e != nil {
+				return *e, true
+			}
+		}
+	}
+}
+
+func (q *concurrentLinkedQueue[E]) Peek() (E, bool) {
+	res, ok := q.zeroVal, false
+	q.each(func(p *concurrentQueueNode[E], e *E) bool {
+		res, ok = *e, true
+		return false
+	})
+	return res, ok
+}
+
+func (q *concurrentLinkedQueue[E]) String() string {
+	return fmt.Sprintf("%v", q.ToArray())
+}
+
+// each 从头部开始遍历未被删除的元素，f 返回 false 时停止遍历
+func (q *concurrentLinkedQueue[E]) each(f func(p *concurrentQueueNode[E], e *E) bool) {
+	for p := q.head.Load().next.Load(); p != nil; p = p.next.Load() {
+		if e := p.item.Load(); e != nil && !f(p, e) {
+			return
+		}
+	}
+}
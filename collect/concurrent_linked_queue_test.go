@@ -0,0 +1,119 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_concurrentLinkedQueue(t *testing.T) {
+	q := NewConcurrentLinkedQueue[int]()
+	if _, ok := q.Take(); ok {
+		t.Error("Take() on empty queue = true, want false")
+	}
+	for i := 1; i <= 5; i++ {
+		q.Put(i)
+	}
+	if e, ok := q.Peek(); !ok || e != 1 {
+		t.Errorf("Peek() = %v, %v, want 1, true", e, ok)
+	}
+	if !q.Remove(1) || !q.Remove(3) || q.Remove(3) {
+		t.Error("Remove() mismatch")
+	}
+	if e, ok := q.Peek(); !ok || e != 2 {
+		t.Errorf("Peek() = %v, %v, want 2, true", e, ok)
+	}
+	if got := q.ToArray(); !reflect.DeepEqual(got, []int{2, 4, 5}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{2, 4, 5})
+	}
+	if q.Size() != 3 || !q.Contains(4) || q.Contains(3) {
+		t.Errorf("queue = %v, want %v", q, []int{2, 4, 5})
+	}
+	if e, ok := q.Take(); !ok || e != 2 {
+		t.Errorf("Take() = %v, %v, want 2, true", e, ok)
+	}
+	if n := q.RemoveIf(func(e int) bool { return e == 5 }); n != 1 {
+		t.Errorf("RemoveIf() = %v, want 1", n)
+	}
+	if !q.Equals(wrapArrayList([]int{4})) {
+		t.Errorf("queue = %v, want %v", q, []int{4})
+	}
+	q.Clear()
+	if !q.IsEmpty() {
+		t.Error("IsEmpty() = false, want true")
+	}
+	q.Put(6)
+	if e, ok := q.Take(); !ok || e != 6 {
+		t.Errorf("Take() = %v, %v, want 6, true", e, ok)
+	}
+}
+
+func Test_concurrentLinkedQueue_stress(t *testing.T) {
+	const producers, consumers, n = 8, 8, 2000
+	q := NewConcurrentLinkedQueue[int]()
+	seen := make([]atomic.Int32, producers*n)
+	var taken atomic.Int64
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				q.Put(p*n + i)
+			}
+		}(p)
+	}
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// 每个生产者的元素必须按照插入顺序被取出
+			last := make([]int, producers)
+			for i := range last {
+				last[i] = -1
+			}
+			for taken.Load() < producers*n {
+				e, ok := q.Take()
+				if !ok {
+					q.Peek()
+					continue
+				}
+				taken.Add(1)
+				seen[e].Add(1)
+				if p := e / n; e <= last[p] {
+					t.Errorf("Take() = %v after %v", e, last[p])
+				} else {
+					last[p] = e
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for i := range seen {
+		if seen[i].Load() != 1 {
+			t.Fatalf("element %d taken %d times, want 1", i, seen[i].Load())
+		}
+	}
+	if !q.IsEmpty() {
+		t.Errorf("queue = %v, want empty", q)
+	}
+}
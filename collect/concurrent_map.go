@@ -0,0 +1,448 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ConcurrentMap 并发安全的 Map，键按照哈希值分布到多个独立加锁的分段中，不同分段的写操作互不阻塞
+// Compute、ComputeIfAbsent、Merge 的函数参数在分段的写锁内执行，函数内不能再访问该 Map，否则会死锁
+// ForEach 以及各个视图的迭代都是弱一致的：逐个分段获取快照，不会同时持有所有分段的锁，
+// 迭代过程中的修改可能可见也可能不可见
+type ConcurrentMap[K comparable, V any] interface {
+	Map[K, V]
+
+	// CompareAndSwap 当键存在且映射的值等于 old 时将值替换为 new，返回是否替换成功
+	CompareAndSwap(k K, old, new V) bool
+}
+
+// Hasher 计算键的哈希值，相等的键必须返回相同的哈希值
+type Hasher[K comparable] func(k K) uint64
+
+type ConcurrentMapConfig[K comparable] struct {
+	// Shards 分段个数，会向上取整为 2 的幂，默认 32
+	Shards int
+	// Hasher 键的哈希函数，默认的哈希函数与 == 保持一致：指针和 channel 按照地址计算，结构体和数组逐个字段计算，
+	// 接口按照动态类型和值计算；整数、浮点数、字符串和布尔以外的键使用反射计算，这种情况建议设置该字段以获得更好的性能
+	Hasher Hasher[K]
+}
+
+// NewConcurrentMap 根据配置创建一个 ConcurrentMap，值的比较使用 DefaultEqualFunc
+func NewConcurrentMap[K comparable, V any](config ConcurrentMapConfig[K]) ConcurrentMap[K, V] {
+	if config.Shards < 1 {
+		config.Shards = 32
+	}
+	if config.Hasher == nil {
+		config.Hasher = defaultHasher[K]()
+	}
+	n := 1
+	for n < config.Shards && n < math.MaxInt32 {
+		n <<= 1
+	}
+	m := &concurrentMap[K, V]{
+		shards: make([]*mapShard[K, V], n),
+		mask:   uint64(n - 1),
+		hasher: config.Hasher,
+	}
+	for i := range m.shards {
+		m.shards[i] = &mapShard[K, V]{hashMap: newHashMap[K, V]()}
+	}
+	m.comparator = m.shards[0].comparator
+	return m
+}
+
+// defaultHasher 基于 maphash 的默认哈希函数
+func defaultHasher[K comparable]() Hasher[K] {
+	seed := maphash.MakeSeed()
+	return func(k K) uint64 {
+		switch v := any(k).(type) {
+		case string:
+			return maphash.String(seed, v)
+		case int:
+			return mix64(uint64(v))
+		case int8:
+			return mix64(uint64(v))
+		case int16:
+			return mix64(uint64(v))
+		case int32:
+			return mix64(uint64(v))
+		case int64:
+			return mix64(uint64(v))
+		case uint:
+			return mix64(uint64(v))
+		case uint8:
+			return mix64(uint64(v))
+		case uint16:
+			return mix64(uint64(v))
+		case uint32:
+			return mix64(uint64(v))
+		case uint64:
+			return mix64(v)
+		case uintptr:
+			return mix64(uint64(v))
+		case float32:
+			return hashFloat(float64(v))
+		case float64:
+			return hashFloat(v)
+		case bool:
+			if v {
+				return 1
+			}
+			return 0
+		}
+		var h maphash.Hash
+		h.SetSeed(seed)
+		hashReflect(&h, reflect.ValueOf(&k).Elem())
+		return h.Sum64()
+	}
+}
+
+// hashReflect 按照 == 的语义将 v 写入 h：相等的值写入相同的内容
+func hashReflect(h *maphash.Hash, v reflect.Value) {
+	var buf [8]byte
+	writeUint64 := func(x uint64) {
+		binary.LittleEndian.PutUint64(buf[:], x)
+		h.Write(buf[:])
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint64(floatBits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeUint64(floatBits(real(c)))
+		writeUint64(floatBits(imag(c)))
+	case reflect.String:
+		h.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint64(uint64(v.Pointer()))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashReflect(h, v.Index(i))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			// == 忽略空白字段
+			if t.Field(i).Name != "_" {
+				hashReflect(h, v.Field(i))
+			}
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			h.WriteByte(0)
+			return
+		}
+		h.WriteByte(1)
+		elem := v.Elem()
+		h.WriteString(elem.Type().String())
+		hashReflect(h, elem)
+	default:
+		// 函数、切片、map 不能使用 == 比较，作为接口的动态值时 == 本身就会 panic
+		panic(fmt.Sprintf("collect: unhashable type %v", v.Type()))
+	}
+}
+
+// floatBits 返回浮点数的位表示，+0 和 -0 相等，返回相同的结果
+func floatBits(f float64) uint64 {
+	if f == 0 {
+		return 0
+	}
+	return math.Float64bits(f)
+}
+
+// mix64 打散整数的各个位，避免连续的整数落在同一个分段
+func mix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+func hashFloat(f float64) uint64 {
+	// +0 和 -0 相等，需要返回相同的哈希值
+	if f == 0 {
+		return 0
+	}
+	return mix64(floatBits(f))
+}
+
+type mapShard[K comparable, V any] struct {
+	sync.RWMutex
+	*hashMap[K, V]
+}
+
+type concurrentMap[K comparable, V any] struct {
+	shards     []*mapShard[K, V]
+	mask       uint64
+	hasher     Hasher[K]
+	comparator constraints.EqualComparator[V]
+	zeroVal    V
+}
+
+func (c *concurrentMap[K, V]) shard(k K) *mapShard[K, V] {
+	return c.shards[c.hasher(k)&c.mask]
+}
+
+func (c *concurrentMap[K, V]) Size() int {
+	var size int
+	for _, s := range c.shards {
+		s.RLock()
+		size += s.Size()
+		s.RUnlock()
+	}
+	return size
+}
+
+func (c *concurrentMap[K, V]) IsEmpty() bool {
+	for _, s := range c.shards {
+		s.RLock()
+		empty := s.IsEmpty()
+		s.RUnlock()
+		if !empty {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *concurrentMap[K, V]) Get(k K) (V, bool) {
+	s := c.shard(k)
+	s.RLock()
+	defer s.RUnlock()
+	return s.Get(k)
+}
+
+func (c *concurrentMap[K, V]) GetOrDefault(k K, defaultValue V) V {
+	if v, ok := c.Get(k); ok {
+		return v
+	}
+	return defaultValue
+}
+
+func (c *concurrentMap[K, V]) Put(k K, v V) (V, bool) {
+	s := c.shard(k)
+	s.Lock()
+	defer s.Unlock()
+	return s.Put(k, v)
+}
+
+func (c *concurrentMap[K, V]) PutIfAbsent(k K, v V) (V, bool) {
+	s := c.shard(k)
+	s.Lock()
+	defer s.Unlock()
+	return s.PutIfAbsent(k, v)
+}
+
+func (c *concurrentMap[K, V]) PutAll(m Map[K, V]) {
+	_ = m.ForEach(func(k K, v V) error {
+		c.Put(k, v)
+		return nil
+	})
+}
+
+func (c *concurrentMap[K, V]) Remove(k K) (V, bool) {
+	s := c.shard(k)
+	s.Lock()
+	defer s.Unlock()
+	return s.Remove(k)
+}
+
+func (c *concurrentMap[K, V]) ContainsKey(k K) bool {
+	s := c.shard(k)
+	s.RLock()
+	defer s.RUnlock()
+	return s.ContainsKey(k)
+}
+
+func (c *concurrentMap[K, V]) ContainsValue(v V) bool {
+	for _, s := range c.shards {
+		s.RLock()
+		ok := s.ContainsValue(v)
+		s.RUnlock()
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *concurrentMap[K, V]) Compute(k K, remapping func(k K, old V, exists bool) (V, bool)) (V, bool) {
+	s := c.shard(k)
+	s.Lock()
+	defer s.Unlock()
+	return s.Compute(k, remapping)
+}
+
+func (c *concurrentMap[K, V]) ComputeIfAbsent(k K, mapping func(k K) V) V {
+	s := c.shard(k)
+	// 键已经存在时只需要读锁
+	s.RLock()
+	v, ok := s.Get(k)
+	s.RUnlock()
+	if ok {
+		return v
+	}
+	s.Lock()
+	defer s.Unlock()
+	return s.ComputeIfAbsent(k, mapping)
+}
+
+func (c *concurrentMap[K, V]) Merge(k K, v V, remapping func(old, v V) (V, bool)) (V, bool) {
+	s := c.shard(k)
+	s.Lock()
+	defer s.Unlock()
+	return s.Merge(k, v, remapping)
+}
+
+func (c *concurrentMap[K, V]) CompareAndSwap(k K, old, new V) bool {
+	s := c.shard(k)
+	s.Lock()
+	defer s.Unlock()
+	if e, ok := s.data[k]; ok && c.comparator.Equal(e.value, old) {
+		e.value = new
+		return true
+	}
+	return false
+}
+
+func (c *concurrentMap[K, V]) ForEach(f BiConsumer[K, V]) error {
+	for _, s := range c.shards {
+		for _, e := range c.shardEntries(s) {
+			if err := f(e.Key(), e.Value()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *concurrentMap[K, V]) KeySet() Set[K] {
+	return newKeySetView[K, V](c)
+}
+
+func (c *concurrentMap[K, V]) Values() Collection[V] {
+	return newValuesView[K, V](c)
+}
+
+func (c *concurrentMap[K, V]) EntrySet() Set[Entry[K, V]] {
+	return newEntrySetView[K, V](c)
+}
+
+func (c *concurrentMap[K, V]) Clear() {
+	for _, s := range c.shards {
+		s.Lock()
+		s.Clear()
+		s.Unlock()
+	}
+}
+
+func (c *concurrentMap[K, V]) String() string {
+	build := strings.Builder{}
+	build.WriteString("map[")
+	for i, e := range c.entries() {
+		if i > 0 {
+			build.WriteByte(' ')
+		}
+		build.WriteString(fmt.Sprintf("%v:%v", e.Key(), e.Value()))
+	}
+	build.WriteByte(']')
+	return build.String()
+}
+
+func (c *concurrentMap[K, V]) peek(k K) (V, bool) {
+	return c.Get(k)
+}
+
+// entries 逐个分段获取键值对的快照
+func (c *concurrentMap[K, V]) entries() []Entry[K, V] {
+	var res []Entry[K, V]
+	for _, s := range c.shards {
+		res = append(res, c.shardEntries(s)...)
+	}
+	return res
+}
+
+// shardEntries 在分段的读锁内复制键值对，避免读取快照时和写操作发生数据竞争
+func (c *concurrentMap[K, V]) shardEntries(s *mapShard[K, V]) []Entry[K, V] {
+	s.RLock()
+	defer s.RUnlock()
+	if len(s.data) == 0 {
+		return nil
+	}
+	res := make([]Entry[K, V], 0, len(s.data))
+	for k, e := range s.data {
+		res = append(res, &concurrentEntry[K, V]{key: k, value: e.value, m: c})
+	}
+	return res
+}
+
+func (c *concurrentMap[K, V]) valueComparator() constraints.EqualComparator[V] {
+	return c.comparator
+}
+
+// concurrentEntry 键值对的快照，SetValue 会写回到 Map 中，键已经被删除时不会重新加入
+type concurrentEntry[K comparable, V any] struct {
+	key   K
+	value V
+	m     *concurrentMap[K, V]
+}
+
+func (e *concurrentEntry[K, V]) Key() K {
+	return e.key
+}
+
+func (e *concurrentEntry[K, V]) Value() V {
+	return e.value
+}
+
+func (e *concurrentEntry[K, V]) SetValue(v V) V {
+	old := e.value
+	e.value = v
+	s := e.m.shard(e.key)
+	s.Lock()
+	defer s.Unlock()
+	if me, ok := s.data[e.key]; ok {
+		me.value = v
+	}
+	return old
+}
+
+func (e *concurrentEntry[K, V]) String() string {
+	return fmt.Sprintf("%v=%v", e.key, e.value)
+}
@@ -0,0 +1,206 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_concurrentMap_concurrent(t *testing.T) {
+	m := NewConcurrentMap[string, int](ConcurrentMapConfig[string]{Shards: 4})
+	keys := []string{"a", "b", "c", "d", "e"}
+	var wg sync.WaitGroup
+	var created atomic.Int32
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				k := keys[i%len(keys)]
+				m.Merge(k, 1, func(old, v int) (int, bool) {
+					return old + v, true
+				})
+				m.ComputeIfAbsent("once", func(k string) int {
+					created.Add(1)
+					return 0
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if created.Load() != 1 {
+		t.Errorf("ComputeIfAbsent() called %d times, want 1", created.Load())
+	}
+	for _, k := range keys {
+		if v, _ := m.Get(k); v != 1600 {
+			t.Errorf("Get(%v) = %v, want 1600", k, v)
+		}
+	}
+	if m.Size() != 6 {
+		t.Errorf("Size() = %v, want 6", m.Size())
+	}
+}
+
+func Test_concurrentMap_CompareAndSwap(t *testing.T) {
+	m := NewConcurrentMap[int, int](ConcurrentMapConfig[int]{})
+	m.Put(1, 0)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				for {
+					old, _ := m.Get(1)
+					if m.CompareAndSwap(1, old, old+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := m.Get(1); v != 800 {
+		t.Errorf("Get(1) = %v, want 800", v)
+	}
+	if m.CompareAndSwap(2, 0, 1) {
+		t.Error("CompareAndSwap() on absent key = true, want false")
+	}
+}
+
+func Test_concurrentMap_PutIfAbsent(t *testing.T) {
+	m := NewConcurrentMap[int, int](ConcurrentMapConfig[int]{
+		Shards: 3,
+		Hasher: func(k int) uint64 { return uint64(k) },
+	})
+	var wg sync.WaitGroup
+	var wins atomic.Int32
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			if _, loaded := m.PutIfAbsent(7, g); !loaded {
+				wins.Add(1)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if wins.Load() != 1 {
+		t.Errorf("PutIfAbsent() succeeded %d times, want 1", wins.Load())
+	}
+	if len(m.(*concurrentMap[int, int]).shards) != 4 {
+		t.Errorf("shards = %v, want 4", len(m.(*concurrentMap[int, int]).shards))
+	}
+}
+
+func Test_concurrentMap_ForEach(t *testing.T) {
+	m := NewConcurrentMap[float64, int](ConcurrentMapConfig[float64]{})
+	for i := 0; i < 100; i++ {
+		m.Put(float64(i), i)
+	}
+	// 迭代过程中修改 Map 不会死锁
+	err := m.ForEach(func(k float64, v int) error {
+		if v%2 == 0 {
+			m.Remove(k)
+		} else {
+			m.Put(k, -v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("ForEach() = %v, want nil", err)
+	}
+	if m.Size() != 50 {
+		t.Errorf("Size() = %v, want 50", m.Size())
+	}
+	m.Put(0, 1)
+	var negZero float64
+	negZero = -negZero
+	if v, ok := m.Get(negZero); !ok || v != 1 {
+		t.Errorf("Get(-0) = %v, %v, want 1, true", v, ok)
+	}
+	it := m.EntrySet().Iterator()
+	for it.HasNext() {
+		e, _ := it.Next()
+		e.SetValue(e.Value() * 10)
+	}
+	if v, _ := m.Get(1); v != -10 {
+		t.Errorf("Get(1) = %v, want -10", v)
+	}
+	m.KeySet().RemoveIf(func(k float64) bool {
+		return k > 10
+	})
+	keys := sortedKeys[float64, int](m)
+	if !reflect.DeepEqual(keys, []float64{0, 1, 3, 5, 7, 9}) {
+		t.Errorf("KeySet() = %v, want %v", keys, []float64{0, 1, 3, 5, 7, 9})
+	}
+}
+
+func Test_concurrentMap_defaultHasher(t *testing.T) {
+	type point struct {
+		X, Y float64
+		_    int
+	}
+	type node struct {
+		N int
+	}
+	hasher := defaultHasher[point]()
+	var negZero float64
+	negZero = -negZero
+	if hasher(point{X: 0, Y: 1}) != hasher(point{X: negZero, Y: 1}) {
+		t.Errorf("equal struct keys have different hashes")
+	}
+
+	// 指针按照地址计算哈希值，修改指向的值不影响查找
+	m := NewConcurrentMap[*node, int](ConcurrentMapConfig[*node]{Shards: 64})
+	k := &node{N: 1}
+	m.Put(k, 1)
+	k.N = 12345
+	if v, ok := m.Get(k); !ok || v != 1 {
+		t.Errorf("Get() = %v, %v, want 1, true", v, ok)
+	}
+	if _, ok := m.Get(&node{N: 12345}); ok {
+		t.Errorf("Get() with another pointer found the key")
+	}
+
+	type key struct {
+		V any
+	}
+	ifaceHasher := defaultHasher[key]()
+	if ifaceHasher(key{k}) != ifaceHasher(key{k}) || ifaceHasher(key{"a"}) != ifaceHasher(key{"a"}) {
+		t.Errorf("interface hash is not consistent with ==")
+	}
+}
+
+func Test_concurrentEntry_SetValue(t *testing.T) {
+	m := NewConcurrentMap[string, int](ConcurrentMapConfig[string]{})
+	m.Put("a", 1)
+	e := m.EntrySet().ToArray()[0]
+	m.Remove("a")
+	// 键已经被删除，SetValue 不会重新加入
+	if old := e.SetValue(2); old != 1 {
+		t.Errorf("SetValue() = %v, want 1", old)
+	}
+	if m.ContainsKey("a") {
+		t.Errorf("SetValue() resurrected removed key")
+	}
+}
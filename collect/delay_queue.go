@@ -0,0 +1,330 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"context"
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Delayed 延迟元素接口
+type Delayed interface {
+	// Deadline 返回元素的到期时间，到期之前元素不能从 DelayQueue 中取出
+	Deadline() time.Time
+}
+
+// NewDelayQueue 创建一个无界的延迟队列，队列头部为最先到期的元素
+// Take、TakeCtx、Poll 和 DrainTo 只会取出已经到期的元素，Peek 返回队列头部的元素，不论是否到期
+// 参数 clock 为队列使用的时钟，为 nil 时使用 SystemClock
+func NewDelayQueue[E interface {
+	comparable
+	Delayed
+}](clock Clock) BlockingQueue[E] {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &delayQueue[E]{
+		clock: clock,
+		pq: NewPriorityQueue[E](func(e1, e2 E) bool {
+			return e1.Deadline().Before(e2.Deadline())
+		}),
+	}
+}
+
+type delayQueue[E interface {
+	comparable
+	Delayed
+}] struct {
+	clock   Clock
+	pq      PriorityQueue[E]
+	zeroVal E
+
+	lock sync.Mutex
+	// available 队列头部发生变化时通知等待者
+	available notifier
+}
+
+func (d *delayQueue[E]) Size() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.pq.Size()
+}
+
+func (d *delayQueue[E]) IsEmpty() bool {
+	return d.Size() == 0
+}
+
+func (d *delayQueue[E]) Contains(e E) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.pq.Contains(e)
+}
+
+func (d *delayQueue[E]) Iterator() Iterator[E] {
+	return newSnapshotIterator[E](d)
+}
+
+func (d *delayQueue[E]) ToArray() []E {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.pq.ToArray()
+}
+
+func (d *delayQueue[E]) Add(e E) bool {
+	return d.Put(e)
+}
+
+func (d *delayQueue[E]) Remove(e E) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.pq.Remove(e) {
+		d.available.broadcast()
+		return true
+	}
+	return false
+}
+
+func (d *delayQueue[E]) ContainsAll(c Collection[E]) bool {
+	itr := c.Iterator()
+	for itr.HasNext() {
+		if e, err := itr.Next(); err != nil || !d.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *delayQueue[E]) AddAll(c Collection[E]) {
+	arr := c.ToArray()
+	if len(arr) == 0 {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, e := range arr {
+		d.pq.Put(e)
+	}
+	d.available.broadcast()
+}
+
+func (d *delayQueue[E]) RemoveAll(c Collection[E]) int {
+	return d.RemoveIf(func(e E) bool {
+		return c.Contains(e)
+	})
+}
+
+// RemoveIf 在队列的快照上调用 filter，调用时不持有锁，filter 中可以访问队列
+// 之后删除队列中所有与 filter 返回 true 的元素相等的元素，包括快照之后加入的相等元素
+func (d *delayQueue[E]) RemoveIf(filter Predicate[E]) int {
+	matched := matchSnapshot(d.ToArray(), filter)
+	if len(matched) == 0 {
+		return 0
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	cnt := d.pq.RemoveIf(func(e E) bool {
+		_, ok := matched[e]
+		return ok
+	})
+	if cnt > 0 {
+		d.available.broadcast()
+	}
+	return cnt
+}
+
+func (d *delayQueue[E]) RetainAll(c Collection[E]) int {
+	return d.RemoveIf(func(e E) bool {
+		return !c.Contains(e)
+	})
+}
+
+func (d *delayQueue[E]) Clear() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.pq.Clear()
+	d.available.broadcast()
+}
+
+func (d *delayQueue[E]) Equals(c Collection[E]) bool {
+	return equals[E](d, c)
+}
+
+// ForEach 迭代队列中元素的快照，迭代过程中不持有锁
+func (d *delayQueue[E]) ForEach(f Consumer[E]) error {
+	for _, e := range d.ToArray() {
+		if err := f(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// All 迭代队列中元素的快照，迭代过程中不持有锁
+func (d *delayQueue[E]) All() iter.Seq[E] {
+	return slices.Values(d.ToArray())
+}
+
+func (d *delayQueue[E]) GetEqualComparator() constraints.EqualComparator[E] {
+	return comparableEqual[E]()
+}
+
+func (d *delayQueue[E]) Put(e E) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.pq.Put(e)
+	d.available.broadcast()
+	return true
+}
+
+// Take 获取并移除队列头部已经到期的元素
+// 如果队列为空或者头部元素还未到期，第二个返回值为 false
+func (d *delayQueue[E]) Take() (E, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if _, ok := d.expired(); !ok {
+		return d.zeroVal, false
+	}
+	return d.take(), true
+}
+
+func (d *delayQueue[E]) Peek() (E, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.pq.Peek()
+}
+
+// PutCtx 延迟队列是无界的，不会阻塞
+func (d *delayQueue[E]) PutCtx(_ context.Context, e E) error {
+	d.Put(e)
+	return nil
+}
+
+// TakeCtx 获取并移除队列头部的元素，如果队列为空或者头部元素还未到期则阻塞等待
+func (d *delayQueue[E]) TakeCtx(ctx context.Context) (E, error) {
+	return d.await(ctx, nil)
+}
+
+// Offer 延迟队列是无界的，不会阻塞
+func (d *delayQueue[E]) Offer(e E, _ time.Duration) bool {
+	return d.Put(e)
+}
+
+// Poll 获取并移除队列头部已经到期的元素，最多等待 timeout 时长，等待时长由队列的时钟计算
+func (d *delayQueue[E]) Poll(timeout time.Duration) (E, bool) {
+	if timeout <= 0 {
+		return d.Take()
+	}
+	timer := d.clock.NewTimer(timeout)
+	defer timer.Stop()
+	e, err := d.await(context.Background(), timer.C())
+	return e, err == nil
+}
+
+func (d *delayQueue[E]) RemainingCapacity() int {
+	return math.MaxInt
+}
+
+// DrainTo 移除最多 max 个已经到期的元素，并批量加入到集合 c 中
+func (d *delayQueue[E]) DrainTo(c Collection[E], max int) int {
+	d.lock.Lock()
+	var drained []E
+	for max < 0 || len(drained) < max {
+		if _, ok := d.expired(); !ok {
+			break
+		}
+		drained = append(drained, d.take())
+	}
+	d.lock.Unlock()
+	if len(drained) == 0 {
+		return 0
+	}
+	c.AddAll(wrapArrayList[E](drained))
+	return len(drained)
+}
+
+func (d *delayQueue[E]) String() string {
+	return fmt.Sprintf("%v", d.ToArray())
+}
+
+// await 等待队列头部元素到期并取出，timeout 触发时返回 context.DeadlineExceeded
+func (d *delayQueue[E]) await(ctx context.Context, timeout <-chan time.Time) (E, error) {
+	d.lock.Lock()
+	for {
+		deadline, ok := d.expired()
+		if ok {
+			e := d.take()
+			d.lock.Unlock()
+			return e, nil
+		}
+		// 队列为空时只等待新元素加入，否则同时等待头部元素到期
+		// 定时器按照头部元素的到期时间创建，计算等待时长之后时钟前进也不会推迟唤醒
+		var timer Timer
+		var expire <-chan time.Time
+		if !deadline.IsZero() {
+			timer = newTimerAt(d.clock, deadline)
+			expire = timer.C()
+		}
+		ch := d.available.wait()
+		d.lock.Unlock()
+		var err error
+		select {
+		case <-ch:
+		case <-expire:
+		case <-timeout:
+			err = context.DeadlineExceeded
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err != nil {
+			return d.zeroVal, err
+		}
+		d.lock.Lock()
+	}
+}
+
+// expired 检查队列头部元素是否已经到期，未到期时返回头部元素的到期时间
+// 队列为空时返回零值和 false，调用前必须持有锁
+func (d *delayQueue[E]) expired() (time.Time, bool) {
+	head, ok := d.pq.Peek()
+	if !ok {
+		return time.Time{}, false
+	}
+	deadline := head.Deadline()
+	if deadline.After(d.clock.Now()) {
+		return deadline, false
+	}
+	return time.Time{}, true
+}
+
+// take 取出队列头部元素，调用前必须持有锁并确保队列不为空
+func (d *delayQueue[E]) take() E {
+	e, _ := d.pq.Take()
+	// 头部元素发生变化，其他等待者需要重新计算等待时长
+	d.available.broadcast()
+	return e
+}
@@ -0,0 +1,198 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type delayedTask struct {
+	name     string
+	deadline time.Time
+}
+
+func (d *delayedTask) Deadline() time.Time {
+	return d.deadline
+}
+
+// waitTimers 等待被测试的 goroutine 在时钟上创建 n 个定时器
+func waitTimers(clock *ManualClock, n int) {
+	for clock.PendingTimers() < n {
+		runtime.Gosched()
+	}
+}
+
+func Test_delayQueue_take(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	q := NewDelayQueue[*delayedTask](clock)
+	a := &delayedTask{"a", clock.Now().Add(2 * time.Second)}
+	b := &delayedTask{"b", clock.Now().Add(time.Second)}
+	q.Put(a)
+	q.Put(b)
+	if e, ok := q.Peek(); !ok || e != b {
+		t.Errorf("Peek() = %v, %v, want b, true", e, ok)
+	}
+	if _, ok := q.Take(); ok {
+		t.Error("Take() ok = true, want false")
+	}
+	clock.Advance(time.Second)
+	if e, ok := q.Take(); !ok || e != b {
+		t.Errorf("Take() = %v, %v, want b, true", e, ok)
+	}
+	if _, ok := q.Take(); ok {
+		t.Error("Take() ok = true, want false")
+	}
+	list := NewList[*delayedTask](DefaultListConfig)
+	if n := q.DrainTo(list, -1); n != 0 {
+		t.Errorf("DrainTo() = %d, want 0", n)
+	}
+	clock.Advance(time.Second)
+	if n := q.DrainTo(list, -1); n != 1 || !list.Contains(a) {
+		t.Errorf("DrainTo() = %d, want 1", n)
+	}
+}
+
+func Test_delayQueue_takeCtx(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	q := NewDelayQueue[*delayedTask](clock)
+	a := &delayedTask{"a", clock.Now().Add(time.Minute)}
+	q.Put(a)
+	done := make(chan *delayedTask)
+	go func() {
+		e, err := q.TakeCtx(context.Background())
+		if err != nil {
+			t.Errorf("TakeCtx() = %v, want nil", err)
+		}
+		done <- e
+	}()
+	waitTimers(clock, 1)
+	// 新加入的元素先到期，等待者需要重新计算等待时长
+	b := &delayedTask{"b", clock.Now().Add(time.Second)}
+	q.Put(b)
+	clock.Advance(time.Second)
+	if e := <-done; e != b {
+		t.Errorf("TakeCtx() = %v, want b", e.name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		_, err := q.TakeCtx(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("TakeCtx() = %v, want Canceled", err)
+		}
+		done <- nil
+	}()
+	waitTimers(clock, 1)
+	cancel()
+	<-done
+	if q.Size() != 1 {
+		t.Errorf("Size() = %d, want 1", q.Size())
+	}
+}
+
+func Test_delayQueue_poll(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	q := NewDelayQueue[*delayedTask](clock)
+	q.Put(&delayedTask{"a", clock.Now().Add(time.Minute)})
+	done := make(chan bool)
+	go func() {
+		_, ok := q.Poll(10 * time.Second)
+		done <- ok
+	}()
+	// Poll 的超时定时器和头部元素的到期定时器
+	waitTimers(clock, 2)
+	clock.Advance(10 * time.Second)
+	if <-done {
+		t.Error("Poll() ok = true, want false")
+	}
+	go func() {
+		_, ok := q.Poll(time.Hour)
+		done <- ok
+	}()
+	waitTimers(clock, 2)
+	clock.Advance(50 * time.Second)
+	if !<-done {
+		t.Error("Poll() ok = false, want true")
+	}
+}
+
+// steppingClock 第一次读取当前时间之后将时钟推进 step，模拟计算等待时长和创建定时器之间时钟前进
+type steppingClock struct {
+	*ManualClock
+	step    time.Duration
+	stepped atomic.Bool
+}
+
+func (c *steppingClock) Now() time.Time {
+	now := c.ManualClock.Now()
+	if c.stepped.CompareAndSwap(false, true) {
+		c.ManualClock.Advance(c.step)
+	}
+	return now
+}
+
+func Test_delayQueue_awaitDeadline(t *testing.T) {
+	clock := &steppingClock{ManualClock: NewManualClock(time.Unix(0, 0)), step: time.Second}
+	q := NewDelayQueue[*delayedTask](clock)
+	a := &delayedTask{"a", time.Unix(2, 0)}
+	q.Put(a)
+	done := make(chan *delayedTask)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		e, err := q.TakeCtx(ctx)
+		if err != nil {
+			t.Errorf("TakeCtx() = %v, want nil", err)
+		}
+		done <- e
+	}()
+	waitTimers(clock.ManualClock, 1)
+	clock.Advance(time.Second)
+	if e := <-done; e != a {
+		t.Errorf("TakeCtx() = %v, want a", e)
+	}
+}
+
+func Test_delayQueue_removeSelf(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	q := NewDelayQueue[*delayedTask](clock)
+	a := &delayedTask{"a", clock.Now().Add(time.Second)}
+	b := &delayedTask{"b", clock.Now().Add(2 * time.Second)}
+	q.Put(a)
+	q.Put(b)
+	// filter 中访问队列不会死锁
+	if n := q.RemoveIf(func(e *delayedTask) bool { return e == a && q.Contains(b) }); n != 1 {
+		t.Errorf("RemoveIf() = %d, want 1", n)
+	}
+	if got := q.ToArray(); !reflect.DeepEqual(got, []*delayedTask{b}) {
+		t.Errorf("ToArray() = %v, want %v", got, []*delayedTask{b})
+	}
+	if n := q.RetainAll(q); n != 0 || q.Size() != 1 {
+		t.Errorf("RetainAll() = %d, size = %d", n, q.Size())
+	}
+	if n := q.RemoveAll(q); n != 1 || !q.IsEmpty() {
+		t.Errorf("RemoveAll() = %d, size = %d", n, q.Size())
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+// Deque 支持在两端插入和删除元素的双端队列
+// 作为队列使用时 Put 等价于 AddLast，Take 等价于 PollFirst，Peek 等价于 PeekFirst
+// 作为栈使用时 Push 等价于 AddFirst，Pop 等价于 PollFirst
+type Deque[E comparable] interface {
+	Queue[E]
+
+	// AddFirst 将元素插入到队列头部
+	AddFirst(e E)
+
+	// AddLast 将元素插入到队列尾部
+	AddLast(e E)
+
+	// PollFirst 获取并移除队列的第一个元素
+	// 如果队列为空，第二个返回值为 false
+	PollFirst() (E, bool)
+
+	// PollLast 获取并移除队列的最后一个元素
+	// 如果队列为空，第二个返回值为 false
+	PollLast() (E, bool)
+
+	// PeekFirst 检索但不删除队列的第一个元素
+	// 如果队列为空，第二个返回值为 false
+	PeekFirst() (E, bool)
+
+	// PeekLast 检索但不删除队列的最后一个元素
+	// 如果队列为空，第二个返回值为 false
+	PeekLast() (E, bool)
+
+	// DescendingIterator 返回从队列尾部到头部逆序迭代的迭代器
+	DescendingIterator() Iterator[E]
+
+	// Push 将元素压入栈顶，即插入到队列头部
+	Push(e E)
+
+	// Pop 弹出栈顶元素，即获取并移除队列的第一个元素
+	// 如果队列为空，第二个返回值为 false
+	Pop() (E, bool)
+}
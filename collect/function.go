@@ -27,3 +27,5 @@ type Predicate[E any] func(e E) bool
 type UnaryOperator[E any] func(e E) E
 
 type SortLess[E any] func(e1, e2 E) bool
+
+type BiConsumer[K, V any] func(k K, v V) error
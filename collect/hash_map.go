@@ -0,0 +1,309 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"strings"
+)
+
+// NewHashMap 创建一个基于 Go map 的 Map，迭代顺序不固定
+// 值的比较使用 DefaultEqualFunc
+func NewHashMap[K comparable, V any]() Map[K, V] {
+	return newHashMap[K, V]()
+}
+
+func newHashMap[K comparable, V any]() *hashMap[K, V] {
+	return &hashMap[K, V]{
+		data: make(map[K]*mapEntry[K, V]),
+		comparator: AnyEqualComparableFunc[V](func(v1, v2 V) bool {
+			return DefaultEqualFunc().Equal(v1, v2)
+		}),
+	}
+}
+
+type mapEntry[K comparable, V any] struct {
+	key   K
+	value V
+	// before after 有序 Map 中的前一个和后一个键值对
+	before, after *mapEntry[K, V]
+}
+
+func (e *mapEntry[K, V]) Key() K {
+	return e.key
+}
+
+func (e *mapEntry[K, V]) Value() V {
+	return e.value
+}
+
+func (e *mapEntry[K, V]) SetValue(v V) V {
+	old := e.value
+	e.value = v
+	return old
+}
+
+func (e *mapEntry[K, V]) String() string {
+	return fmt.Sprintf("%v=%v", e.key, e.value)
+}
+
+type hashMap[K comparable, V any] struct {
+	data       map[K]*mapEntry[K, V]
+	comparator constraints.EqualComparator[V]
+	zeroVal    V
+
+	// linked 为 true 时使用双向链表维护键值对的迭代顺序，head 为最早的键值对
+	// accessOrder 为 true 时按照访问顺序迭代，否则按照插入顺序迭代
+	linked, accessOrder bool
+	head, tail          *mapEntry[K, V]
+}
+
+func (h *hashMap[K, V]) Size() int {
+	return len(h.data)
+}
+
+func (h *hashMap[K, V]) IsEmpty() bool {
+	return len(h.data) == 0
+}
+
+func (h *hashMap[K, V]) Get(k K) (V, bool) {
+	if e, ok := h.data[k]; ok {
+		h.afterAccess(e)
+		return e.value, true
+	}
+	return h.zeroVal, false
+}
+
+func (h *hashMap[K, V]) GetOrDefault(k K, defaultValue V) V {
+	if v, ok := h.Get(k); ok {
+		return v
+	}
+	return defaultValue
+}
+
+func (h *hashMap[K, V]) Put(k K, v V) (V, bool) {
+	if e, ok := h.data[k]; ok {
+		h.afterAccess(e)
+		return e.SetValue(v), true
+	}
+	h.insert(k, v)
+	return h.zeroVal, false
+}
+
+func (h *hashMap[K, V]) PutIfAbsent(k K, v V) (V, bool) {
+	if e, ok := h.data[k]; ok {
+		h.afterAccess(e)
+		return e.value, true
+	}
+	h.insert(k, v)
+	return v, false
+}
+
+func (h *hashMap[K, V]) PutAll(m Map[K, V]) {
+	_ = m.ForEach(func(k K, v V) error {
+		h.Put(k, v)
+		return nil
+	})
+}
+
+func (h *hashMap[K, V]) Remove(k K) (V, bool) {
+	if e, ok := h.data[k]; ok {
+		h.delete(e)
+		return e.value, true
+	}
+	return h.zeroVal, false
+}
+
+func (h *hashMap[K, V]) ContainsKey(k K) bool {
+	_, ok := h.data[k]
+	return ok
+}
+
+func (h *hashMap[K, V]) ContainsValue(v V) bool {
+	for _, e := range h.data {
+		if h.comparator.Equal(v, e.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *hashMap[K, V]) Compute(k K, remapping func(k K, old V, exists bool) (V, bool)) (V, bool) {
+	e, exists := h.data[k]
+	old := h.zeroVal
+	if exists {
+		old = e.value
+	}
+	v, ok := remapping(k, old, exists)
+	if !ok {
+		if exists {
+			h.delete(e)
+		}
+		return h.zeroVal, false
+	}
+	if exists {
+		e.value = v
+		h.afterAccess(e)
+	} else {
+		h.insert(k, v)
+	}
+	return v, true
+}
+
+func (h *hashMap[K, V]) ComputeIfAbsent(k K, mapping func(k K) V) V {
+	if e, ok := h.data[k]; ok {
+		h.afterAccess(e)
+		return e.value
+	}
+	v := mapping(k)
+	h.insert(k, v)
+	return v
+}
+
+func (h *hashMap[K, V]) Merge(k K, v V, remapping func(old, v V) (V, bool)) (V, bool) {
+	return h.Compute(k, func(k K, old V, exists bool) (V, bool) {
+		if !exists {
+			return v, true
+		}
+		return remapping(old, v)
+	})
+}
+
+func (h *hashMap[K, V]) ForEach(f BiConsumer[K, V]) error {
+	if h.linked {
+		for e, next := h.head, h.head; e != nil; e = next {
+			next = e.after
+			if err := f(e.key, e.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for k, e := range h.data {
+		if err := f(k, e.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *hashMap[K, V]) KeySet() Set[K] {
+	return newKeySetView[K, V](h)
+}
+
+func (h *hashMap[K, V]) Values() Collection[V] {
+	return newValuesView[K, V](h)
+}
+
+func (h *hashMap[K, V]) EntrySet() Set[Entry[K, V]] {
+	return newEntrySetView[K, V](h)
+}
+
+func (h *hashMap[K, V]) Clear() {
+	h.data = make(map[K]*mapEntry[K, V])
+	h.head, h.tail = nil, nil
+}
+
+func (h *hashMap[K, V]) String() string {
+	build := strings.Builder{}
+	build.WriteString("map[")
+	for i, e := range h.entries() {
+		if i > 0 {
+			build.WriteByte(' ')
+		}
+		build.WriteString(fmt.Sprintf("%v:%v", e.Key(), e.Value()))
+	}
+	build.WriteByte(']')
+	return build.String()
+}
+
+func (h *hashMap[K, V]) peek(k K) (V, bool) {
+	if e, ok := h.data[k]; ok {
+		return e.value, true
+	}
+	return h.zeroVal, false
+}
+
+func (h *hashMap[K, V]) entries() []Entry[K, V] {
+	res := make([]Entry[K, V], 0, len(h.data))
+	if h.linked {
+		for e := h.head; e != nil; e = e.after {
+			res = append(res, e)
+		}
+		return res
+	}
+	for _, e := range h.data {
+		res = append(res, e)
+	}
+	return res
+}
+
+func (h *hashMap[K, V]) valueComparator() constraints.EqualComparator[V] {
+	return h.comparator
+}
+
+// insert 插入新的键值对，调用前必须确保键不存在
+func (h *hashMap[K, V]) insert(k K, v V) {
+	e := &mapEntry[K, V]{key: k, value: v}
+	h.data[k] = e
+	if h.linked {
+		h.linkLast(e)
+	}
+}
+
+// delete 删除键值对
+func (h *hashMap[K, V]) delete(e *mapEntry[K, V]) {
+	delete(h.data, e.key)
+	if h.linked {
+		h.unlink(e)
+	}
+}
+
+// afterAccess 按照访问顺序迭代时，将被访问的键值对移动到链表尾部
+func (h *hashMap[K, V]) afterAccess(e *mapEntry[K, V]) {
+	if h.accessOrder && h.tail != e {
+		h.unlink(e)
+		h.linkLast(e)
+	}
+}
+
+func (h *hashMap[K, V]) linkLast(e *mapEntry[K, V]) {
+	e.before = h.tail
+	if h.tail == nil {
+		h.head = e
+	} else {
+		h.tail.after = e
+	}
+	h.tail = e
+}
+
+func (h *hashMap[K, V]) unlink(e *mapEntry[K, V]) {
+	if e.before == nil {
+		h.head = e.after
+	} else {
+		e.before.after = e.after
+	}
+	if e.after == nil {
+		h.tail = e.before
+	} else {
+		e.after.before = e.before
+	}
+	e.before, e.after = nil, nil
+}
@@ -0,0 +1,181 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"github.com/yzrzr/go-util/constraints"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func sortedKeys[K constraints.Ordered, V any](m Map[K, V]) []K {
+	keys := m.KeySet().ToArray()
+	slices.Sort(keys)
+	return keys
+}
+
+func Test_hashMap_putGet(t *testing.T) {
+	m := NewHashMap[string, int]()
+	if _, ok := m.Put("a", 1); ok {
+		t.Error("Put() ok = true, want false")
+	}
+	if old, ok := m.Put("a", 2); !ok || old != 1 {
+		t.Errorf("Put() = %v, %v, want 1, true", old, ok)
+	}
+	if v, ok := m.PutIfAbsent("a", 3); !ok || v != 2 {
+		t.Errorf("PutIfAbsent() = %v, %v, want 2, true", v, ok)
+	}
+	if v, ok := m.PutIfAbsent("b", 3); ok || v != 3 {
+		t.Errorf("PutIfAbsent() = %v, %v, want 3, false", v, ok)
+	}
+	if v, ok := m.Get("a"); !ok || v != 2 {
+		t.Errorf("Get() = %v, %v, want 2, true", v, ok)
+	}
+	if v := m.GetOrDefault("c", 10); v != 10 {
+		t.Errorf("GetOrDefault() = %v, want 10", v)
+	}
+	if !m.ContainsKey("b") || m.ContainsKey("c") {
+		t.Error("ContainsKey() result mismatch")
+	}
+	if !m.ContainsValue(3) || m.ContainsValue(4) {
+		t.Error("ContainsValue() result mismatch")
+	}
+	if v, ok := m.Remove("b"); !ok || v != 3 {
+		t.Errorf("Remove() = %v, %v, want 3, true", v, ok)
+	}
+	if _, ok := m.Remove("b"); ok {
+		t.Error("Remove() ok = true, want false")
+	}
+	other := NewHashMap[string, int]()
+	other.PutAll(m)
+	other.Put("x", 9)
+	if got := sortedKeys(other); !reflect.DeepEqual(got, []string{"a", "x"}) {
+		t.Errorf("PutAll() keys = %v, want %v", got, []string{"a", "x"})
+	}
+	m.Clear()
+	if !m.IsEmpty() {
+		t.Errorf("Clear() size = %d, want 0", m.Size())
+	}
+}
+
+func Test_hashMap_compute(t *testing.T) {
+	m := NewHashMap[string, int]()
+	words := []string{"a", "b", "a", "c", "a", "b"}
+	for _, w := range words {
+		m.Merge(w, 1, func(old, v int) (int, bool) {
+			return old + v, true
+		})
+	}
+	for k, want := range map[string]int{"a": 3, "b": 2, "c": 1} {
+		if v, _ := m.Get(k); v != want {
+			t.Errorf("Merge() %s = %d, want %d", k, v, want)
+		}
+	}
+	if v, ok := m.Compute("c", func(k string, old int, exists bool) (int, bool) {
+		return 0, false
+	}); ok || v != 0 || m.ContainsKey("c") {
+		t.Errorf("Compute() = %v, %v, want removed", v, ok)
+	}
+	if v, ok := m.Compute("d", func(k string, old int, exists bool) (int, bool) {
+		if exists {
+			t.Error("Compute() exists = true, want false")
+		}
+		return 4, true
+	}); !ok || v != 4 {
+		t.Errorf("Compute() = %v, %v, want 4, true", v, ok)
+	}
+	calls := 0
+	mapping := func(k string) int {
+		calls++
+		return len(k)
+	}
+	if v := m.ComputeIfAbsent("eee", mapping); v != 3 {
+		t.Errorf("ComputeIfAbsent() = %d, want 3", v)
+	}
+	if v := m.ComputeIfAbsent("eee", mapping); v != 3 || calls != 1 {
+		t.Errorf("ComputeIfAbsent() = %d, calls %d, want 3, 1", v, calls)
+	}
+	if v, ok := m.Merge("a", 1, func(old, v int) (int, bool) { return 0, false }); ok || m.ContainsKey("a") {
+		t.Errorf("Merge() = %v, %v, want removed", v, ok)
+	}
+	sum := 0
+	_ = m.ForEach(func(k string, v int) error {
+		sum += v
+		return nil
+	})
+	if sum != 2+4+3 {
+		t.Errorf("ForEach() sum = %d, want %d", sum, 2+4+3)
+	}
+}
+
+func Test_hashMap_views(t *testing.T) {
+	m := NewHashMap[int, string]()
+	for i := 1; i <= 6; i++ {
+		m.Put(i, string(rune('a'+i-1)))
+	}
+	keys := m.KeySet()
+	if !keys.Contains(3) || keys.Size() != 6 {
+		t.Error("KeySet() mismatch")
+	}
+	if keys.Add(7) {
+		t.Error("KeySet().Add() = true, want false")
+	}
+	if !keys.Remove(1) || m.ContainsKey(1) {
+		t.Error("KeySet().Remove() did not write through")
+	}
+	values := m.Values()
+	if !values.Remove("b") || m.ContainsKey(2) {
+		t.Error("Values().Remove() did not write through")
+	}
+	it := keys.Iterator()
+	for it.HasNext() {
+		k, _ := it.Next()
+		if k == 3 {
+			if err := it.Remove(); err != nil {
+				t.Errorf("Remove() = %v, want nil", err)
+			}
+		}
+	}
+	if got := sortedKeys(m); !reflect.DeepEqual(got, []int{4, 5, 6}) {
+		t.Errorf("keys = %v, want %v", got, []int{4, 5, 6})
+	}
+	for _, e := range m.EntrySet().ToArray() {
+		if e.Key() == 4 {
+			e.SetValue("x")
+		}
+	}
+	if v, _ := m.Get(4); v != "x" {
+		t.Errorf("Entry.SetValue() = %v, want x", v)
+	}
+	entries := m.EntrySet()
+	if !entries.Contains(&mapEntry[int, string]{key: 4, value: "x"}) || entries.Contains(&mapEntry[int, string]{key: 4, value: "d"}) {
+		t.Error("EntrySet().Contains() mismatch")
+	}
+	if n := entries.RemoveIf(func(e Entry[int, string]) bool { return e.Key() > 4 }); n != 2 {
+		t.Errorf("EntrySet().RemoveIf() = %d, want 2", n)
+	}
+	if !keys.Equals(SetOf(4)) || m.Size() != 1 {
+		t.Errorf("map = %v, want map[4:x]", m)
+	}
+	values.Clear()
+	if !m.IsEmpty() {
+		t.Errorf("Values().Clear() size = %d, want 0", m.Size())
+	}
+}
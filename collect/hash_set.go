@@ -21,11 +21,14 @@ package collect
 import (
 	"fmt"
 	"github.com/yzrzr/go-util/constraints"
+	"iter"
 	"strings"
 )
 
 type hashSet[E comparable] struct {
 	data map[E]struct{}
+	// jsonLess 序列化为 JSON 时元素的排序方法，为 nil 时不排序
+	jsonLess SortLess[E]
 }
 
 func (h *hashSet[E]) Size() int {
@@ -133,6 +136,16 @@ func (h *hashSet[E]) ForEach(f Consumer[E]) error {
 	return nil
 }
 
+func (h *hashSet[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for k := range h.data {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
 func (h *hashSet[E]) GetEqualComparator() constraints.EqualComparator[E] {
 	return nil
 }
@@ -0,0 +1,234 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// 所有集合都序列化为 JSON 数组，空集合序列化为 []
+// 反序列化会先清空集合，再按照数组的顺序依次调用 Add 加入元素，JSON null 不会修改集合
+// 作为结构体字段反序列化时字段需要预先初始化，字段为 nil 时可以使用 UnmarshalList、UnmarshalSet 创建
+
+// UnmarshalList 将 JSON 数组反序列化为 List，底层实现由 config 和 options 决定，与 NewList 一致
+func UnmarshalList[E any](data []byte, config ListConfig, options ...ListOption) (List[E], error) {
+	list := NewList[E](config, options...)
+	if err := unmarshalJSON[E](list, data); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// UnmarshalSet 将 JSON 数组反序列化为 Set，重复的元素只会保留一个
+func UnmarshalSet[E comparable](data []byte, options ...SetOption[E]) (Set[E], error) {
+	set := NewSet[E](options...)
+	if err := unmarshalJSON[E](set, data); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+func marshalJSON[E any](values []E) ([]byte, error) {
+	if values == nil {
+		values = []E{}
+	}
+	return json.Marshal(values)
+}
+
+func unmarshalJSON[E any](c Collection[E], data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	var values []E
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	resetCollection(c, values)
+	return nil
+}
+
+// resetCollection 清空集合 c，再按照顺序依次加入 values 中的元素
+func resetCollection[E any](c Collection[E], values []E) {
+	c.Clear()
+	for _, e := range values {
+		c.Add(e)
+	}
+}
+
+func (a *arrayList[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(a.ToArray())
+}
+
+func (a *arrayList[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON[E](a, data)
+}
+
+func (l *linkedList[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(l.ToArray())
+}
+
+func (l *linkedList[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON[E](l, data)
+}
+
+func (s *subList[E]) MarshalJSON() ([]byte, error) {
+	values, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return marshalJSON(values)
+}
+
+func (s *subList[E]) UnmarshalJSON(data []byte) error {
+	if err := s.checkForComodification(); err != nil {
+		return err
+	}
+	return unmarshalJSON[E](s, data)
+}
+
+func (a *safeList[E]) MarshalJSON() ([]byte, error) {
+	a.RLock()
+	defer a.RUnlock()
+	if err := checkView(a.List); err != nil {
+		return nil, err
+	}
+	return marshalJSON(a.List.ToArray())
+}
+
+func (a *safeList[E]) UnmarshalJSON(data []byte) error {
+	a.Lock()
+	defer a.Unlock()
+	if err := checkView(a.List); err != nil {
+		return err
+	}
+	return unmarshalJSON[E](a.List, data)
+}
+
+func (h *hashSet[E]) MarshalJSON() ([]byte, error) {
+	values := h.ToArray()
+	if h.jsonLess != nil {
+		sort.Slice(values, func(i, j int) bool {
+			return h.jsonLess(values[i], values[j])
+		})
+	}
+	return marshalJSON(values)
+}
+
+func (h *hashSet[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON[E](h, data)
+}
+
+func (s *mapSet[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s.ToArray())
+}
+
+func (s *mapSet[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON[E](s, data)
+}
+
+func (s *treeSet[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON[E](s, data)
+}
+
+func (a *safeSet[E]) MarshalJSON() ([]byte, error) {
+	a.RLock()
+	defer a.RUnlock()
+	if m, ok := a.Set.(json.Marshaler); ok {
+		return m.MarshalJSON()
+	}
+	return marshalJSON(a.Set.ToArray())
+}
+
+func (a *safeSet[E]) UnmarshalJSON(data []byte) error {
+	a.Lock()
+	defer a.Unlock()
+	return unmarshalJSON[E](a.Set, data)
+}
+
+func (q *arrayBlockingQueue[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(q.ToArray())
+}
+
+func (q *arrayBlockingQueue[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON[E](q, data)
+}
+
+func (q *linkedBlockingQueue[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(q.ToArray())
+}
+
+func (q *linkedBlockingQueue[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON[E](q, data)
+}
+
+func (q *concurrentLinkedQueue[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(q.ToArray())
+}
+
+func (q *concurrentLinkedQueue[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON[E](q, data)
+}
+
+func (p *priorityQueue[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p.ToArray())
+}
+
+func (p *priorityQueue[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON[E](p, data)
+}
+
+func (d *delayQueue[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(d.ToArray())
+}
+
+func (d *delayQueue[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON[E](d, data)
+}
+
+func (d *arrayDeque[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(d.ToArray())
+}
+
+func (d *arrayDeque[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON[E](d, data)
+}
+
+func (m *mapView[K, V, E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(m.ToArray())
+}
+
+// entryJSON 键值对序列化为 JSON 对象的格式
+type entryJSON[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+func (e *mapEntry[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(entryJSON[K, V]{Key: e.key, Value: e.value})
+}
+
+func (n *treeNode[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(entryJSON[K, V]{Key: n.key, Value: n.value})
+}
+
+func (e *concurrentEntry[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(entryJSON[K, V]{Key: e.key, Value: e.value})
+}
@@ -0,0 +1,109 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type jsonDTO struct {
+	Ids  List[int]    `json:"ids"`
+	Tags Set[string]  `json:"tags"`
+	Data List[string] `json:"data,omitempty"`
+}
+
+func Test_json_list(t *testing.T) {
+	for _, c := range configList {
+		list := newArrayList[int](c, 3, 1, 2)
+		data, err := json.Marshal(list)
+		if err != nil || string(data) != "[3,1,2]" {
+			t.Errorf("Marshal() = %s, %v, want [3,1,2], nil", data, err)
+		}
+		got, err := UnmarshalList[int](data, c)
+		if err != nil || !got.Equals(list) {
+			t.Errorf("UnmarshalList() = %v, %v, want %v, nil", got, err, list)
+		}
+		if data, _ := json.Marshal(NewList[int](c)); string(data) != "[]" {
+			t.Errorf("Marshal() = %s, want []", data)
+		}
+	}
+	if _, err := UnmarshalList[int]([]byte(`["a"]`), DefaultListConfig); err == nil {
+		t.Error("UnmarshalList() error = nil, want error")
+	}
+}
+
+func Test_json_struct(t *testing.T) {
+	dto := jsonDTO{
+		Ids:  newArrayList[int](DefaultListConfig, 1, 2),
+		Tags: NewSet(WithJSONLess(SortLessOrdered[string](true))),
+	}
+	dto.Tags.AddAll(wrapArrayList([]string{"c", "a", "b"}))
+	data, err := json.Marshal(dto)
+	if want := `{"ids":[1,2],"tags":["a","b","c"]}`; err != nil || string(data) != want {
+		t.Errorf("Marshal() = %s, %v, want %s, nil", data, err, want)
+	}
+	var got jsonDTO
+	got.Ids = NewList[int](ListConfig{DataStruct: DataStructLinked, Safe: true})
+	got.Ids.Add(100)
+	got.Tags = NewLinkedHashSet[string]()
+	if err := json.Unmarshal([]byte(`{"ids":[4,5,6],"tags":["z","x","z"]}`), &got); err != nil {
+		t.Fatalf("Unmarshal() = %v, want nil", err)
+	}
+	if !reflect.DeepEqual(got.Ids.ToArray(), []int{4, 5, 6}) {
+		t.Errorf("Ids = %v, want %v", got.Ids, []int{4, 5, 6})
+	}
+	if !reflect.DeepEqual(got.Tags.ToArray(), []string{"z", "x"}) {
+		t.Errorf("Tags = %v, want %v", got.Tags, []string{"z", "x"})
+	}
+}
+
+func Test_json_others(t *testing.T) {
+	tree := NewOrderedTreeSet[int](false)
+	if err := json.Unmarshal([]byte(`[1,3,2]`), tree); err != nil {
+		t.Fatalf("Unmarshal() = %v, want nil", err)
+	}
+	m := NewOrderedTreeMap[string, int](true)
+	m.Put("b", 2)
+	m.Put("a", 1)
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"treeSet", tree, "[3,2,1]"},
+		{"safeSet", NewSafeSet(NewSet(WithJSONLess(SortLessOrdered[int](true)))), "[]"},
+		{"arrayDeque", NewArrayDeque[int](0), "[]"},
+		{"keySet", m.KeySet(), `["a","b"]`},
+		{"entrySet", m.EntrySet(), `[{"key":"a","value":1},{"key":"b","value":2}]`},
+	}
+	for _, tt := range tests {
+		if data, err := json.Marshal(tt.v); err != nil || string(data) != tt.want {
+			t.Errorf("%s Marshal() = %s, %v, want %s, nil", tt.name, data, err, tt.want)
+		}
+	}
+	q := NewLinkedBlockingQueue[int](2)
+	if err := json.Unmarshal([]byte(`[7,8,9]`), q); err != nil {
+		t.Fatalf("Unmarshal() = %v, want nil", err)
+	}
+	if data, _ := json.Marshal(q); string(data) != "[7,8]" {
+		t.Errorf("Marshal() = %s, want [7,8]", data)
+	}
+}
@@ -0,0 +1,394 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"context"
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NewLinkedBlockingQueue 创建一个基于链表的阻塞队列
+// 参数 capacity 为队列容量，小于等于0表示不限制容量
+// 队列头部和尾部分别使用独立的锁，生产者和消费者之间不会互相竞争
+func NewLinkedBlockingQueue[E comparable](capacity int) BlockingQueue[E] {
+	if capacity <= 0 {
+		capacity = math.MaxInt
+	}
+	head := &linkedQueueNode[E]{}
+	return &linkedBlockingQueue[E]{
+		capacity: capacity,
+		head:     head,
+		tail:     head,
+	}
+}
+
+type linkedQueueNode[E any] struct {
+	item E
+	next *linkedQueueNode[E]
+}
+
+type linkedBlockingQueue[E comparable] struct {
+	capacity int
+	count    atomic.Int64
+	zeroVal  E
+
+	// head 哨兵节点，head.next 为队列的第一个元素，由 takeLock 保护
+	// tail 队列的最后一个节点，由 putLock 保护
+	head, tail *linkedQueueNode[E]
+
+	takeLock sync.Mutex
+	notEmpty notifier
+	putLock  sync.Mutex
+	notFull  notifier
+}
+
+func (q *linkedBlockingQueue[E]) Size() int {
+	return int(q.count.Load())
+}
+
+func (q *linkedBlockingQueue[E]) IsEmpty() bool {
+	return q.Size() == 0
+}
+
+func (q *linkedBlockingQueue[E]) Contains(e E) bool {
+	q.fullyLock()
+	defer q.fullyUnlock()
+	for p := q.head.next; p != nil; p = p.next {
+		if p.item == e {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *linkedBlockingQueue[E]) Iterator() Iterator[E] {
+	return newSnapshotIterator[E](q)
+}
+
+func (q *linkedBlockingQueue[E]) ToArray() []E {
+	q.fullyLock()
+	defer q.fullyUnlock()
+	size := q.Size()
+	if size == 0 {
+		return nil
+	}
+	res := make([]E, 0, size)
+	for p := q.head.next; p != nil; p = p.next {
+		res = append(res, p.item)
+	}
+	return res
+}
+
+func (q *linkedBlockingQueue[E]) Add(e E) bool {
+	return q.Put(e)
+}
+
+func (q *linkedBlockingQueue[E]) Remove(e E) bool {
+	q.fullyLock()
+	defer q.fullyUnlock()
+	for trail, p := q.head, q.head.next; p != nil; trail, p = p, p.next {
+		if p.item == e {
+			q.unlink(p, trail)
+			return true
+		}
+	}
+	return false
+}
+
+func (q *linkedBlockingQueue[E]) ContainsAll(c Collection[E]) bool {
+	itr := c.Iterator()
+	for itr.HasNext() {
+		if e, err := itr.Next(); err != nil || !q.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddAll 将指定集合中的元素依次加入队列，队列已满时剩余的元素会被丢弃
+func (q *linkedBlockingQueue[E]) AddAll(c Collection[E]) {
+	for _, e := range c.ToArray() {
+		if !q.Put(e) {
+			return
+		}
+	}
+}
+
+func (q *linkedBlockingQueue[E]) RemoveAll(c Collection[E]) int {
+	return q.RemoveIf(func(e E) bool {
+		return c.Contains(e)
+	})
+}
+
+// RemoveIf 在队列的快照上调用 filter，调用时不持有锁，filter 中可以访问队列
+// 之后删除队列中所有与 filter 返回 true 的元素相等的元素，包括快照之后加入的相等元素
+func (q *linkedBlockingQueue[E]) RemoveIf(filter Predicate[E]) int {
+	matched := matchSnapshot(q.ToArray(), filter)
+	if len(matched) == 0 {
+		return 0
+	}
+	q.fullyLock()
+	defer q.fullyUnlock()
+	var cnt int
+	for trail, p := q.head, q.head.next; p != nil; p = trail.next {
+		if _, ok := matched[p.item]; ok {
+			q.unlink(p, trail)
+			cnt++
+		} else {
+			trail = p
+		}
+	}
+	return cnt
+}
+
+func (q *linkedBlockingQueue[E]) RetainAll(c Collection[E]) int {
+	return q.RemoveIf(func(e E) bool {
+		return !c.Contains(e)
+	})
+}
+
+func (q *linkedBlockingQueue[E]) Clear() {
+	q.fullyLock()
+	defer q.fullyUnlock()
+	q.head.next = nil
+	q.tail = q.head
+	if q.count.Swap(0) == int64(q.capacity) {
+		q.notFull.broadcast()
+	}
+}
+
+func (q *linkedBlockingQueue[E]) Equals(c Collection[E]) bool {
+	return equals[E](q, c)
+}
+
+// ForEach 迭代队列中元素的快照，迭代过程中不持有锁
+func (q *linkedBlockingQueue[E]) ForEach(f Consumer[E]) error {
+	for _, e := range q.ToArray() {
+		if err := f(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// All 迭代队列中元素的快照，迭代过程中不持有锁
+func (q *linkedBlockingQueue[E]) All() iter.Seq[E] {
+	return slices.Values(q.ToArray())
+}
+
+func (q *linkedBlockingQueue[E]) GetEqualComparator() constraints.EqualComparator[E] {
+	return comparableEqual[E]()
+}
+
+func (q *linkedBlockingQueue[E]) Put(e E) bool {
+	q.putLock.Lock()
+	if q.Size() == q.capacity {
+		q.putLock.Unlock()
+		return false
+	}
+	c := q.enqueue(e)
+	q.putLock.Unlock()
+	if c == 0 {
+		q.signalNotEmpty()
+	}
+	return true
+}
+
+func (q *linkedBlockingQueue[E]) Take() (E, bool) {
+	if q.Size() == 0 {
+		return q.zeroVal, false
+	}
+	q.takeLock.Lock()
+	if q.Size() == 0 {
+		q.takeLock.Unlock()
+		return q.zeroVal, false
+	}
+	e, c := q.dequeue()
+	q.takeLock.Unlock()
+	if c == q.capacity {
+		q.signalNotFull()
+	}
+	return e, true
+}
+
+func (q *linkedBlockingQueue[E]) Peek() (E, bool) {
+	q.takeLock.Lock()
+	defer q.takeLock.Unlock()
+	// 先检查 count，保证读取 head.next 时能够看到 Put 操作的写入
+	if q.Size() == 0 {
+		return q.zeroVal, false
+	}
+	return q.head.next.item, true
+}
+
+func (q *linkedBlockingQueue[E]) PutCtx(ctx context.Context, e E) error {
+	q.putLock.Lock()
+	for q.Size() == q.capacity {
+		ch := q.notFull.wait()
+		q.putLock.Unlock()
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		q.putLock.Lock()
+	}
+	c := q.enqueue(e)
+	q.putLock.Unlock()
+	if c == 0 {
+		q.signalNotEmpty()
+	}
+	return nil
+}
+
+func (q *linkedBlockingQueue[E]) TakeCtx(ctx context.Context) (E, error) {
+	q.takeLock.Lock()
+	for q.Size() == 0 {
+		ch := q.notEmpty.wait()
+		q.takeLock.Unlock()
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return q.zeroVal, ctx.Err()
+		}
+		q.takeLock.Lock()
+	}
+	e, c := q.dequeue()
+	q.takeLock.Unlock()
+	if c == q.capacity {
+		q.signalNotFull()
+	}
+	return e, nil
+}
+
+func (q *linkedBlockingQueue[E]) Offer(e E, timeout time.Duration) bool {
+	if timeout <= 0 {
+		return q.Put(e)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return q.PutCtx(ctx, e) == nil
+}
+
+func (q *linkedBlockingQueue[E]) Poll(timeout time.Duration) (E, bool) {
+	if timeout <= 0 {
+		return q.Take()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	e, err := q.TakeCtx(ctx)
+	return e, err == nil
+}
+
+func (q *linkedBlockingQueue[E]) RemainingCapacity() int {
+	return q.capacity - q.Size()
+}
+
+func (q *linkedBlockingQueue[E]) DrainTo(c Collection[E], max int) int {
+	q.takeLock.Lock()
+	n := q.Size()
+	if max >= 0 && max < n {
+		n = max
+	}
+	if n == 0 {
+		q.takeLock.Unlock()
+		return 0
+	}
+	drained := make([]E, n)
+	var full bool
+	for i := 0; i < n; i++ {
+		var cnt int
+		drained[i], cnt = q.dequeue()
+		full = full || cnt == q.capacity
+	}
+	q.takeLock.Unlock()
+	if full {
+		q.signalNotFull()
+	}
+	c.AddAll(wrapArrayList[E](drained))
+	return n
+}
+
+func (q *linkedBlockingQueue[E]) String() string {
+	return fmt.Sprintf("%v", q.ToArray())
+}
+
+// enqueue 将元素链接到队列尾部，调用前必须持有 putLock 并确保队列未满
+// 返回插入前的元素个数
+func (q *linkedBlockingQueue[E]) enqueue(e E) int {
+	node := &linkedQueueNode[E]{item: e}
+	q.tail.next = node
+	q.tail = node
+	return int(q.count.Add(1)) - 1
+}
+
+// dequeue 移除队列头部的元素，调用前必须持有 takeLock 并确保队列不为空
+// 返回被移除的元素和移除前的元素个数
+func (q *linkedBlockingQueue[E]) dequeue() (E, int) {
+	first := q.head.next
+	q.head.next = nil
+	q.head = first
+	e := first.item
+	first.item = q.zeroVal
+	return e, int(q.count.Add(-1)) + 1
+}
+
+// unlink 将节点 p 从链表中移除，trail 为 p 的前一个节点，调用前必须持有全部锁
+func (q *linkedBlockingQueue[E]) unlink(p, trail *linkedQueueNode[E]) {
+	p.item = q.zeroVal
+	trail.next = p.next
+	if q.tail == p {
+		q.tail = trail
+	}
+	if int(q.count.Add(-1))+1 == q.capacity {
+		q.notFull.broadcast()
+	}
+}
+
+// signalNotEmpty 唤醒等待获取元素的 goroutine，只能在 Put 操作之后调用
+func (q *linkedBlockingQueue[E]) signalNotEmpty() {
+	q.takeLock.Lock()
+	q.notEmpty.broadcast()
+	q.takeLock.Unlock()
+}
+
+// signalNotFull 唤醒等待插入元素的 goroutine，只能在 Take 操作之后调用
+func (q *linkedBlockingQueue[E]) signalNotFull() {
+	q.putLock.Lock()
+	q.notFull.broadcast()
+	q.putLock.Unlock()
+}
+
+// fullyLock 同时锁住队列的头部和尾部
+func (q *linkedBlockingQueue[E]) fullyLock() {
+	q.putLock.Lock()
+	q.takeLock.Lock()
+}
+
+func (q *linkedBlockingQueue[E]) fullyUnlock() {
+	q.takeLock.Unlock()
+	q.putLock.Unlock()
+}
@@ -0,0 +1,184 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_linkedBlockingQueue_putTake(t *testing.T) {
+	q := NewLinkedBlockingQueue[int](2)
+	if !q.Put(1) || !q.Put(2) {
+		t.Error("Put() = false, want true")
+	}
+	if q.Put(3) {
+		t.Error("Put(3) = true, want false")
+	}
+	if e, ok := q.Peek(); !ok || e != 1 {
+		t.Errorf("Peek() = %v, %v, want 1, true", e, ok)
+	}
+	for _, want := range []int{1, 2} {
+		if e, ok := q.Take(); !ok || e != want {
+			t.Errorf("Take() = %v, %v, want %v, true", e, ok, want)
+		}
+	}
+	if _, ok := q.Take(); ok {
+		t.Error("Take() ok = true, want false")
+	}
+
+	unbounded := NewLinkedBlockingQueue[int](0)
+	for i := 0; i < 100; i++ {
+		if !unbounded.Put(i) {
+			t.Errorf("Put(%d) = false, want true", i)
+		}
+	}
+	if unbounded.Size() != 100 {
+		t.Errorf("Size() = %d, want 100", unbounded.Size())
+	}
+}
+
+func Test_linkedBlockingQueue_remove(t *testing.T) {
+	q := NewLinkedBlockingQueue[int](0)
+	for _, v := range []int{1, 2, 3, 4, 5} {
+		q.Put(v)
+	}
+	if !q.Remove(5) {
+		t.Error("Remove(5) = false, want true")
+	}
+	q.Put(6)
+	if n := q.RemoveIf(func(e int) bool { return e%2 == 1 }); n != 2 {
+		t.Errorf("RemoveIf() = %d, want 2", n)
+	}
+	if got := q.ToArray(); !reflect.DeepEqual(got, []int{2, 4, 6}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{2, 4, 6})
+	}
+	q.Clear()
+	if !q.IsEmpty() {
+		t.Errorf("Clear() size = %d, want 0", q.Size())
+	}
+	q.Put(7)
+	if got := q.ToArray(); !reflect.DeepEqual(got, []int{7}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{7})
+	}
+}
+
+func Test_linkedBlockingQueue_removeSelf(t *testing.T) {
+	q := NewLinkedBlockingQueue[int](5)
+	for _, v := range []int{1, 2, 3, 2} {
+		q.Put(v)
+	}
+	// filter 中访问队列不会死锁
+	if n := q.RemoveIf(func(e int) bool { return q.Contains(e + 1) }); n != 3 {
+		t.Errorf("RemoveIf() = %d, want 3", n)
+	}
+	if got := q.ToArray(); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{3})
+	}
+	if n := q.RemoveAll(q); n != 1 || !q.IsEmpty() {
+		t.Errorf("RemoveAll() = %d, size = %d", n, q.Size())
+	}
+}
+
+func Test_linkedBlockingQueue_drainTo(t *testing.T) {
+	for _, q := range []BlockingQueue[int]{NewLinkedBlockingQueue[int](0), NewArrayBlockingQueue[int](10)} {
+		for i := 1; i <= 5; i++ {
+			q.Put(i)
+		}
+		for _, c := range configList {
+			list := newArrayList[int](c, 0)
+			if n := q.DrainTo(list, 2); n != 2 {
+				t.Errorf("DrainTo() = %d, want 2", n)
+			}
+			checkData(t, list, []int{0, 1, 2})
+			q.Put(1)
+			q.Put(2)
+			q.Clear()
+			for i := 1; i <= 5; i++ {
+				q.Put(i)
+			}
+		}
+		list := NewList[int](DefaultListConfig)
+		if n := q.DrainTo(list, -1); n != 5 {
+			t.Errorf("DrainTo() = %d, want 5", n)
+		}
+		checkData(t, list, []int{1, 2, 3, 4, 5})
+		if !q.IsEmpty() {
+			t.Errorf("Size() = %d, want 0", q.Size())
+		}
+	}
+}
+
+func Test_linkedBlockingQueue_ctx(t *testing.T) {
+	q := NewLinkedBlockingQueue[int](1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.TakeCtx(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TakeCtx() = %v, want DeadlineExceeded", err)
+	}
+	q.Put(1)
+	if q.Offer(2, 10*time.Millisecond) {
+		t.Error("Offer() = true, want false")
+	}
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		q.Take()
+	}()
+	if !q.Offer(2, time.Second) {
+		t.Error("Offer() = false, want true")
+	}
+}
+
+func Test_linkedBlockingQueue_producerConsumer(t *testing.T) {
+	q := NewLinkedBlockingQueue[int](8)
+	const producers, n = 4, 500
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				_ = q.PutCtx(context.Background(), i)
+			}
+		}()
+	}
+	results := make(chan int, producers)
+	for c := 0; c < producers; c++ {
+		go func() {
+			var sum int
+			for i := 0; i < n; i++ {
+				e, _ := q.TakeCtx(context.Background())
+				sum += e
+			}
+			results <- sum
+		}()
+	}
+	wg.Wait()
+	var sum int
+	for c := 0; c < producers; c++ {
+		sum += <-results
+	}
+	if want := producers * n * (n - 1) / 2; sum != want {
+		t.Errorf("sum = %d, want %d", sum, want)
+	}
+}
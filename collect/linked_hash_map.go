@@ -0,0 +1,33 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+// NewLinkedHashMap 创建一个迭代顺序固定的 Map
+// 参数 accessOrder 为 false 时按照键的插入顺序迭代，重新插入已存在的键不影响顺序
+// accessOrder 为 true 时按照访问顺序迭代，最近访问的键排在最后，Get、Put、PutIfAbsent、Compute、ComputeIfAbsent、Merge 都视为访问
+func NewLinkedHashMap[K comparable, V any](accessOrder bool) Map[K, V] {
+	return newLinkedHashMap[K, V](accessOrder)
+}
+
+func newLinkedHashMap[K comparable, V any](accessOrder bool) *hashMap[K, V] {
+	m := newHashMap[K, V]()
+	m.linked = true
+	m.accessOrder = accessOrder
+	return m
+}
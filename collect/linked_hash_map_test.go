@@ -0,0 +1,127 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_linkedHashMap_insertionOrder(t *testing.T) {
+	m := NewLinkedHashMap[string, int](false)
+	for i, k := range []string{"c", "a", "d", "b"} {
+		m.Put(k, i)
+	}
+	m.Put("a", 10)
+	m.Get("c")
+	if got := m.KeySet().ToArray(); !reflect.DeepEqual(got, []string{"c", "a", "d", "b"}) {
+		t.Errorf("KeySet() = %v, want %v", got, []string{"c", "a", "d", "b"})
+	}
+	m.Remove("c")
+	m.Put("c", 0)
+	m.Compute("d", func(k string, old int, exists bool) (int, bool) {
+		return 0, false
+	})
+	if got := m.Values().ToArray(); !reflect.DeepEqual(got, []int{10, 3, 0}) {
+		t.Errorf("Values() = %v, want %v", got, []int{10, 3, 0})
+	}
+	var keys []string
+	_ = m.ForEach(func(k string, v int) error {
+		keys = append(keys, k)
+		return nil
+	})
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("ForEach() = %v, want %v", keys, []string{"a", "b", "c"})
+	}
+	if got := m.(interface{ String() string }).String(); got != "map[a:10 b:3 c:0]" {
+		t.Errorf("String() = %v, want map[a:10 b:3 c:0]", got)
+	}
+	m.Clear()
+	m.Put("z", 1)
+	if got := m.KeySet().ToArray(); !reflect.DeepEqual(got, []string{"z"}) {
+		t.Errorf("KeySet() = %v, want %v", got, []string{"z"})
+	}
+}
+
+func Test_linkedHashMap_accessOrder(t *testing.T) {
+	m := NewLinkedHashMap[int, int](true)
+	for i := 1; i <= 5; i++ {
+		m.Put(i, i)
+	}
+	m.Get(1)
+	m.Put(3, 30)
+	m.GetOrDefault(2, 0)
+	m.ComputeIfAbsent(4, func(k int) int { return k })
+	if got := m.KeySet().ToArray(); !reflect.DeepEqual(got, []int{5, 1, 3, 2, 4}) {
+		t.Errorf("KeySet() = %v, want %v", got, []int{5, 1, 3, 2, 4})
+	}
+	// EntrySet 的 Contains 和 Remove 不算作访问
+	entries := m.EntrySet()
+	other := NewLinkedHashMap[int, int](true)
+	other.Put(1, 100)
+	if !entries.Contains(entries.ToArray()[0]) || entries.Remove(other.EntrySet().ToArray()[0]) {
+		t.Errorf("EntrySet() Contains/Remove returned unexpected result")
+	}
+	if got := m.KeySet().ToArray(); !reflect.DeepEqual(got, []int{5, 1, 3, 2, 4}) {
+		t.Errorf("KeySet() = %v, want %v", got, []int{5, 1, 3, 2, 4})
+	}
+}
+
+func Test_linkedHashSet(t *testing.T) {
+	s := []int{5, 3, 9, 1, 7, 3, 5}
+	set := LinkedHashSetOf(s...)
+	want := []int{5, 3, 9, 1, 7}
+	for i := 0; i < 3; i++ {
+		if got := set.ToArray(); !reflect.DeepEqual(got, want) {
+			t.Errorf("ToArray() = %v, want %v", got, want)
+		}
+	}
+	if set.Add(3) {
+		t.Error("Add(3) = true, want false")
+	}
+	if got, want := set.(interface{ String() string }).String(), "[5 3 9 1 7]"; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+	it := set.Iterator()
+	if err := it.Remove(); !errors.Is(err, ErrIllegalState) {
+		t.Errorf("Remove() = %v, want ErrIllegalState", err)
+	}
+	for it.HasNext() {
+		v, _ := it.Next()
+		if v > 5 {
+			if err := it.Remove(); err != nil {
+				t.Errorf("Remove() = %v, want nil", err)
+			}
+		}
+	}
+	set.Add(2)
+	if got := set.ToArray(); !reflect.DeepEqual(got, []int{5, 3, 1, 2}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{5, 3, 1, 2})
+	}
+	if !set.Equals(SetOf(1, 2, 3, 5)) {
+		t.Errorf("Equals() = false, want true")
+	}
+	if n := set.RetainAll(SetOf(1, 2)); n != 2 {
+		t.Errorf("RetainAll() = %d, want 2", n)
+	}
+	if got := set.ToArray(); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{1, 2})
+	}
+}
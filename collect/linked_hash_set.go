@@ -0,0 +1,32 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+// NewLinkedHashSet 创建一个按照插入顺序迭代的 Set，重新插入已存在的元素不影响顺序
+func NewLinkedHashSet[E comparable]() Set[E] {
+	return newMapSet[E](newLinkedHashMap[E, struct{}](false))
+}
+
+func LinkedHashSetOf[E comparable](list ...E) Set[E] {
+	set := NewLinkedHashSet[E]()
+	for _, v := range list {
+		set.Add(v)
+	}
+	return set
+}
@@ -22,6 +22,7 @@ import (
 	"container/list"
 	"fmt"
 	"github.com/yzrzr/go-util/constraints"
+	"iter"
 	"sort"
 	"strings"
 )
@@ -37,6 +38,8 @@ type linkedList[E any] struct {
 	list       *list.List
 	zeroVal    E
 	comparator constraints.EqualComparator[E]
+	// modCount 添加、删除元素的累计次数，迭代器使用它检测并发修改
+	modCount int
 }
 
 func (l *linkedList[E]) Size() int {
@@ -69,6 +72,7 @@ func (l *linkedList[E]) ToArray() []E {
 
 func (l *linkedList[E]) Add(e E) bool {
 	l.list.PushBack(e)
+	l.modCount++
 	return true
 }
 
@@ -99,6 +103,7 @@ func (l *linkedList[E]) AddAll(c Collection[E]) {
 		l.list.PushBack(e)
 		return nil
 	})
+	l.modCount++
 }
 
 func (l *linkedList[E]) RemoveAll(c Collection[E]) int {
@@ -157,6 +162,38 @@ func (l *linkedList[E]) ForEach(f Consumer[E]) error {
 	return nil
 }
 
+func (l *linkedList[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for cur := l.list.Front(); cur != nil; cur = cur.Next() {
+			if !yield(cur.Value.(E)) {
+				return
+			}
+		}
+	}
+}
+
+func (l *linkedList[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for cur := l.list.Back(); cur != nil; cur = cur.Prev() {
+			if !yield(cur.Value.(E)) {
+				return
+			}
+		}
+	}
+}
+
+func (l *linkedList[E]) All2() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		i := 0
+		for cur := l.list.Front(); cur != nil; cur = cur.Next() {
+			if !yield(i, cur.Value.(E)) {
+				return
+			}
+			i++
+		}
+	}
+}
+
 func (l *linkedList[E]) ReplaceAll(operator UnaryOperator[E]) {
 	if operator == nil {
 		return
@@ -210,6 +247,7 @@ func (l *linkedList[E]) AddAt(index int, e E) error {
 		return err
 	}
 	l.list.InsertBefore(e, element)
+	l.modCount++
 	return nil
 }
 
@@ -250,13 +288,7 @@ func (l *linkedList[E]) ListIteratorAt(index int) ListIterator[E] {
 }
 
 func (l *linkedList[E]) SubList(fromIndex, toIndex int) List[E] {
-	newLinkedList := NewLinkedList[E](l.comparator)
-	itr := l.ListIteratorAt(fromIndex)
-	for itr.NextIndex() < toIndex && itr.HasNext() {
-		e, _ := itr.Next()
-		newLinkedList.Add(e)
-	}
-	return newLinkedList
+	return newSubList[E](l, nil, fromIndex, toIndex, l.Size())
 }
 
 func (l *linkedList[E]) GetEqualComparator() constraints.EqualComparator[E] {
@@ -296,6 +328,11 @@ func (l *linkedList[E]) getElement(index int) (*list.Element, error) {
 
 func (l *linkedList[E]) removeElement(e *list.Element) {
 	l.list.Remove(e)
+	l.modCount++
+}
+
+func (l *linkedList[E]) structuralModCount() int {
+	return l.modCount
 }
 
 func (l *linkedList[E]) rangeCheck(index int) error {
@@ -20,6 +20,7 @@ package collect
 
 import (
 	"github.com/yzrzr/go-util/constraints"
+	"iter"
 	"reflect"
 )
 
@@ -52,13 +53,24 @@ type List[E any] interface {
 	// LastIndexOf 返回此列表中指定元素的最后一次出现的索引，如果此列表不包含元素，则返回-1。
 	LastIndexOf(e E) int
 
+	// Backward 返回从尾部向头部迭代元素的 iter.Seq
+	// for e := range list.Backward() {}
+	Backward() iter.Seq[E]
+
+	// All2 返回迭代索引和元素的 iter.Seq2
+	// for i, e := range list.All2() {}
+	All2() iter.Seq2[int, E]
+
 	// ListIterator 返回列表迭代器
 	ListIterator() ListIterator[E]
 
 	// ListIteratorAt 返回列表迭代器,指定开始迭代位置
 	ListIteratorAt(index int) ListIterator[E]
 
-	// SubList 返回列表中指定的fromIndex （含）和toIndex之间的部分
+	// SubList 返回列表中指定的fromIndex （含）和toIndex之间的部分组成的视图
+	// 视图和原列表共享数据，对视图的读写和结构性修改都会作用到原列表上
+	// 通过视图以外的操作添加、删除原列表的元素后视图失效，再访问视图返回 ErrConcurrentModification 或者 panic
+	// 需要独立的副本时使用 CopyOf(list.SubList(fromIndex, toIndex))
 	SubList(fromIndex, toIndex int) List[E]
 
 	// RemoveN 从集合中移除指定的元素
@@ -109,18 +121,17 @@ func NewList[E any](config ListConfig, options ...ListOption) List[E] {
 	if config.InitialCapacity < 1 {
 		config.InitialCapacity = 16
 	}
-	if config.EqualComparator == nil {
-		config.EqualComparator = DefaultEqualFunc()
+	comparator := defaultEqualComparator[E]()
+	if config.EqualComparator != nil {
+		comparator = AnyEqualComparableFunc[E](func(v1, v2 E) bool {
+			return config.EqualComparator.Equal(v1, v2)
+		})
 	}
 	var list List[E]
 	if config.DataStruct == DataStructLinked {
-		list = NewLinkedList[E](AnyEqualComparableFunc[E](func(v1, v2 E) bool {
-			return config.EqualComparator.Equal(v1, v2)
-		}))
+		list = NewLinkedList[E](comparator)
 	} else {
-		list = NewArrayList[E](config.InitialCapacity, AnyEqualComparableFunc[E](func(v1, v2 E) bool {
-			return config.EqualComparator.Equal(v1, v2)
-		}))
+		list = NewArrayList[E](config.InitialCapacity, comparator)
 	}
 	if config.Safe {
 		list = NewSafeList[E](list)
@@ -28,8 +28,16 @@ var (
 	ErrNoSuchElement = errors.New("no such element")
 	ErrIllegalState  = errors.New("illegal state")
 	ErrIteratorClose = errors.New("iterator is close")
+	// ErrConcurrentModification 创建迭代器之后，List 被迭代器以外的操作进行了结构性修改（添加、删除元素）
+	ErrConcurrentModification = errors.New("concurrent modification")
 )
 
+// modCounter 记录结构性修改次数的 List，迭代器使用它检测迭代过程中 List 是否被其它操作修改
+type modCounter interface {
+	// structuralModCount 返回 List 被添加、删除元素的累计次数
+	structuralModCount() int
+}
+
 type ListIterator[E any] interface {
 	Iterator[E]
 
@@ -47,11 +55,16 @@ type ListIterator[E any] interface {
 }
 
 func newListIterator[E any](list List[E], start int) ListIterator[E] {
-	return &listIterator[E]{
+	l := &listIterator[E]{
 		lastRet: -1,
 		cursor:  start,
 		list:    list,
 	}
+	if counter, ok := list.(modCounter); ok {
+		l.counter = counter
+		l.expectedModCount = counter.structuralModCount()
+	}
+	return l
 }
 
 type listIterator[E any] struct {
@@ -61,13 +74,26 @@ type listIterator[E any] struct {
 
 	isClose bool
 	list    List[E]
+
+	// counter 不为 nil 时，迭代器在 Next、Previous、Remove 时检查 List 的修改次数是否与 expectedModCount 一致
+	counter          modCounter
+	expectedModCount int
+}
+
+// checkForComodification List 被迭代器以外的操作修改过时返回 ErrConcurrentModification
+func (l *listIterator[E]) checkForComodification() error {
+	if l.counter != nil && l.counter.structuralModCount() != l.expectedModCount {
+		return ErrConcurrentModification
+	}
+	return nil
 }
 
+// HasNext 发现 List 被迭代器以外的操作修改过时返回 true，由 Next 返回 ErrConcurrentModification
 func (l *listIterator[E]) HasNext() bool {
 	if l.isClose {
 		return false
 	}
-	return l.cursor < l.list.Size()
+	return l.checkForComodification() != nil || l.cursor < l.list.Size()
 }
 
 func (l *listIterator[E]) Next() (e E, err error) {
@@ -75,6 +101,9 @@ func (l *listIterator[E]) Next() (e E, err error) {
 		err = ErrIteratorClose
 		return
 	}
+	if err = l.checkForComodification(); err != nil {
+		return
+	}
 	i := l.cursor
 	if i >= l.list.Size() {
 		err = ErrNoSuchElement
@@ -96,12 +125,18 @@ func (l *listIterator[E]) Remove() error {
 	if l.lastRet < 0 {
 		return ErrIllegalState
 	}
+	if err := l.checkForComodification(); err != nil {
+		return err
+	}
 	_, err := l.list.RemoveAt(l.lastRet)
 	if err != nil {
 		return err
 	}
 	l.cursor = l.lastRet
 	l.lastRet = -1
+	if l.counter != nil {
+		l.expectedModCount = l.counter.structuralModCount()
+	}
 	return nil
 }
 
@@ -109,6 +144,9 @@ func (l *listIterator[E]) ForEachRemaining(action Consumer[E]) error {
 	if l.isClose {
 		return ErrIteratorClose
 	}
+	if err := l.checkForComodification(); err != nil {
+		return err
+	}
 	size := l.list.Size()
 	var err error
 	var e E
@@ -134,6 +172,9 @@ func (l *listIterator[E]) Previous() (e E, err error) {
 		err = ErrIteratorClose
 		return
 	}
+	if err = l.checkForComodification(); err != nil {
+		return
+	}
 	i := l.lastRet
 	if i < 0 {
 		err = ErrNoSuchElement
@@ -165,9 +206,10 @@ func (l *listIterator[E]) Close() {
 func newLinkedListIterator[E any](list *linkedList[E], start int) ListIterator[E] {
 	e, _ := list.getElement(start)
 	return &linkedListIterator[E]{
-		cursor:    e,
-		nextIndex: start,
-		list:      list,
+		cursor:           e,
+		nextIndex:        start,
+		list:             list,
+		expectedModCount: list.modCount,
 	}
 }
 
@@ -178,6 +220,16 @@ type linkedListIterator[E any] struct {
 	list            *linkedList[E]
 	nextIndex       int
 	isClose         bool
+	// expectedModCount 迭代器期望的 List 修改次数，不一致说明 List 被迭代器以外的操作修改过
+	expectedModCount int
+}
+
+// checkForComodification List 被迭代器以外的操作修改过时返回 ErrConcurrentModification
+func (l *linkedListIterator[E]) checkForComodification() error {
+	if l.list.modCount != l.expectedModCount {
+		return ErrConcurrentModification
+	}
+	return nil
 }
 
 func (l *linkedListIterator[E]) HasNext() bool {
@@ -189,6 +241,9 @@ func (l *linkedListIterator[E]) Next() (e E, err error) {
 		err = ErrIteratorClose
 		return
 	}
+	if err = l.checkForComodification(); err != nil {
+		return
+	}
 	cur := l.cursor
 	if cur == nil {
 		err = ErrNoSuchElement
@@ -207,8 +262,12 @@ func (l *linkedListIterator[E]) Remove() error {
 	if l.lastRet == nil {
 		return ErrIllegalState
 	}
+	if err := l.checkForComodification(); err != nil {
+		return err
+	}
 	l.list.removeElement(l.lastRet)
 	l.lastRet = nil
+	l.expectedModCount = l.list.modCount
 	return nil
 }
 
@@ -216,6 +275,9 @@ func (l *linkedListIterator[E]) ForEachRemaining(action Consumer[E]) error {
 	if l.isClose {
 		return ErrIteratorClose
 	}
+	if err := l.checkForComodification(); err != nil {
+		return err
+	}
 	var err error
 	cur := l.cursor
 	for cur != nil {
@@ -237,6 +299,9 @@ func (l *linkedListIterator[E]) Previous() (e E, err error) {
 		err = ErrIteratorClose
 		return
 	}
+	if err = l.checkForComodification(); err != nil {
+		return
+	}
 	if !l.HasPrevious() {
 		err = ErrNoSuchElement
 		return
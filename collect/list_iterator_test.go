@@ -236,3 +236,45 @@ func Test_listIteratorAt(t *testing.T) {
 		}
 	}
 }
+
+func Test_listIterator_concurrentModification(t *testing.T) {
+	for _, c := range configList {
+		if c.Safe {
+			// 安全的迭代器持有读锁，外部的写操作会被阻塞
+			continue
+		}
+		list := newArrayList[int](c, 1, 2, 3, 4)
+		it := list.ListIterator()
+		_, _ = it.Next()
+		if err := it.Remove(); err != nil {
+			t.Errorf("Remove() = %v, want nil", err)
+		}
+		if v, err := it.Next(); err != nil || v != 2 {
+			t.Errorf("Next() = %v, %v, want 2, nil", v, err)
+		}
+		list.Set(0, 20)
+		if _, err := it.Next(); err != nil {
+			t.Errorf("Next() after Set() = %v, want nil", err)
+		}
+		list.RemoveAt(0)
+		if _, err := it.Next(); !errors.Is(err, ErrConcurrentModification) {
+			t.Errorf("Next() = %v, want ErrConcurrentModification", err)
+		}
+		if err := it.Remove(); !errors.Is(err, ErrConcurrentModification) {
+			t.Errorf("Remove() = %v, want ErrConcurrentModification", err)
+		}
+		if _, err := it.Previous(); !errors.Is(err, ErrConcurrentModification) {
+			t.Errorf("Previous() = %v, want ErrConcurrentModification", err)
+		}
+		it = list.ListIterator()
+		list.Add(5)
+		if err := it.ForEachRemaining(func(e int) error { return nil }); !errors.Is(err, ErrConcurrentModification) {
+			t.Errorf("ForEachRemaining() = %v, want ErrConcurrentModification", err)
+		}
+		it = list.Iterator().(ListIterator[int])
+		list.Clear()
+		if _, err := it.Next(); !errors.Is(err, ErrConcurrentModification) {
+			t.Errorf("Next() after Clear() = %v, want ErrConcurrentModification", err)
+		}
+	}
+}
@@ -253,6 +253,21 @@ func Test_arrayList_ForEach(t *testing.T) {
 	}
 }
 
+func Test_arrayList_ForEachCapacity(t *testing.T) {
+	// 底层数组的容量大于元素个数时，ForEach 只访问有效的元素
+	list := NewArrayList[int](10, comparableEqual[int]())
+	list.Add(1)
+	list.Add(2)
+	var got []int
+	list.ForEach(func(e int) error {
+		got = append(got, e)
+		return nil
+	})
+	if !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("ForEach() visited %v, want [1 2]", got)
+	}
+}
+
 func Test_arrayList_Get(t *testing.T) {
 	s := []int{5, 10, 9}
 	for _, c := range configList {
@@ -807,3 +822,34 @@ func Test_arrayList_grow(t *testing.T) {
 	list.grow(256)
 	list.grow(257)
 }
+
+func Test_arrayList_All(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 10, 9, 8, 7}
+	for _, c := range configList {
+		list := newArrayList[int](c, s...)
+		var got []int
+		for e := range list.All() {
+			got = append(got, e)
+		}
+		if !reflect.DeepEqual(got, s) {
+			t.Errorf("All() = %v, want %v", got, s)
+		}
+		got = got[:0]
+		for e := range list.Backward() {
+			if e == 4 {
+				break
+			}
+			got = append(got, e)
+		}
+		if want := []int{7, 8, 9, 10, 5}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Backward() = %v, want %v", got, want)
+		}
+		for i, e := range list.All2() {
+			if e != s[i] {
+				t.Errorf("All2() index %d = %v, want %v", i, e, s[i])
+			}
+		}
+		// 安全的 List 在 range 循环结束后释放读锁
+		list.Add(11)
+	}
+}
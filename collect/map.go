@@ -0,0 +1,95 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+// Entry 键值对
+type Entry[K comparable, V any] interface {
+	// Key 返回键
+	Key() K
+
+	// Value 返回值
+	Value() V
+
+	// SetValue 替换值，返回旧值
+	// 通过 EntrySet 获取的键值对，修改会写回到 Map 中
+	SetValue(v V) V
+}
+
+// Map 键值对映射接口，每个键最多映射到一个值
+type Map[K comparable, V any] interface {
+	// Size 返回键值对的个数
+	Size() int
+
+	// IsEmpty 如果不包含键值对，则返回 true
+	IsEmpty() bool
+
+	// Get 返回键映射的值，如果键不存在第二个返回值为 false
+	Get(k K) (V, bool)
+
+	// GetOrDefault 返回键映射的值，如果键不存在返回 defaultValue
+	GetOrDefault(k K, defaultValue V) V
+
+	// Put 将键映射到指定的值，返回旧值，如果之前键不存在第二个返回值为 false
+	Put(k K, v V) (V, bool)
+
+	// PutIfAbsent 如果键不存在，将键映射到指定的值，返回 v, false
+	// 如果键已经存在，不做修改，返回已经存在的值, true
+	PutIfAbsent(k K, v V) (V, bool)
+
+	// PutAll 将指定 Map 中所有的键值对复制到此 Map 中
+	PutAll(m Map[K, V])
+
+	// Remove 删除键的映射，返回被删除的值，如果键不存在第二个返回值为 false
+	Remove(k K) (V, bool)
+
+	// ContainsKey 如果包含指定的键，则返回true
+	ContainsKey(k K) bool
+
+	// ContainsValue 如果有一个或多个键映射到指定的值，则返回true
+	ContainsValue(v V) bool
+
+	// Compute 使用 remapping 函数计算键的新值，exists 表示键是否存在，不存在时 old 为零值
+	// remapping 第二个返回值为 false 时删除键的映射
+	// 返回计算后的值，如果计算后键不存在第二个返回值为 false
+	Compute(k K, remapping func(k K, old V, exists bool) (V, bool)) (V, bool)
+
+	// ComputeIfAbsent 如果键不存在，使用 mapping 函数计算值并保存
+	// 返回键当前映射的值
+	ComputeIfAbsent(k K, mapping func(k K) V) V
+
+	// Merge 如果键不存在，将键映射到 v；如果键已存在，使用 remapping 函数合并旧值和 v
+	// remapping 第二个返回值为 false 时删除键的映射
+	// 返回合并后的值，如果合并后键不存在第二个返回值为 false
+	Merge(k K, v V, remapping func(old, v V) (V, bool)) (V, bool)
+
+	// ForEach 迭代所有的键值对，直到所有键值对都被处理或返回错误
+	ForEach(f BiConsumer[K, V]) error
+
+	// KeySet 返回所有键组成的 Set 视图，对视图的删除操作会写回到 Map 中，视图不支持添加元素
+	KeySet() Set[K]
+
+	// Values 返回所有值组成的 Collection 视图，对视图的删除操作会写回到 Map 中，视图不支持添加元素
+	Values() Collection[V]
+
+	// EntrySet 返回所有键值对组成的 Set 视图，对视图的删除操作会写回到 Map 中，视图不支持添加元素
+	EntrySet() Set[Entry[K, V]]
+
+	// Clear 删除所有键值对
+	Clear()
+}
@@ -0,0 +1,121 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+)
+
+// newMapSet 创建一个使用 m 的键保存元素的 Set，迭代顺序与 m 一致
+func newMapSet[E comparable](m viewableMap[E, struct{}]) *mapSet[E] {
+	return &mapSet[E]{
+		m:    m,
+		keys: newKeySetView[E, struct{}](m),
+	}
+}
+
+type mapSet[E comparable] struct {
+	m viewableMap[E, struct{}]
+	// keys m 的 KeySet 视图，除 Add 以外的操作都委托给视图
+	keys Set[E]
+}
+
+func (s *mapSet[E]) Size() int {
+	return s.m.Size()
+}
+
+func (s *mapSet[E]) IsEmpty() bool {
+	return s.m.IsEmpty()
+}
+
+func (s *mapSet[E]) Contains(e E) bool {
+	return s.m.ContainsKey(e)
+}
+
+func (s *mapSet[E]) Iterator() Iterator[E] {
+	return s.keys.Iterator()
+}
+
+func (s *mapSet[E]) ToArray() []E {
+	return s.keys.ToArray()
+}
+
+func (s *mapSet[E]) Add(e E) bool {
+	_, ok := s.m.PutIfAbsent(e, struct{}{})
+	return !ok
+}
+
+func (s *mapSet[E]) Remove(e E) bool {
+	_, ok := s.m.Remove(e)
+	return ok
+}
+
+func (s *mapSet[E]) ContainsAll(c Collection[E]) bool {
+	return s.keys.ContainsAll(c)
+}
+
+func (s *mapSet[E]) AddAll(c Collection[E]) {
+	_ = c.ForEach(func(e E) error {
+		s.Add(e)
+		return nil
+	})
+}
+
+func (s *mapSet[E]) RemoveAll(c Collection[E]) int {
+	return s.keys.RemoveAll(c)
+}
+
+func (s *mapSet[E]) RemoveIf(filter Predicate[E]) int {
+	return s.keys.RemoveIf(filter)
+}
+
+func (s *mapSet[E]) RetainAll(c Collection[E]) int {
+	return s.keys.RetainAll(c)
+}
+
+func (s *mapSet[E]) Clear() {
+	s.m.Clear()
+}
+
+func (s *mapSet[E]) Equals(c Collection[E]) bool {
+	if Collection[E](s) == c {
+		return true
+	}
+	return s.keys.Equals(c)
+}
+
+func (s *mapSet[E]) ForEach(f Consumer[E]) error {
+	return s.m.ForEach(func(k E, _ struct{}) error {
+		return f(k)
+	})
+}
+
+func (s *mapSet[E]) All() iter.Seq[E] {
+	return forEachSeq(s.ForEach)
+}
+
+func (s *mapSet[E]) GetEqualComparator() constraints.EqualComparator[E] {
+	return comparableEqual[E]()
+}
+
+func (s *mapSet[E]) String() string {
+	return fmt.Sprintf("%v", s.ToArray())
+}
@@ -0,0 +1,286 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+	"math"
+)
+
+// viewableMap 可以创建 KeySet、Values、EntrySet 视图的 Map
+type viewableMap[K comparable, V any] interface {
+	Map[K, V]
+
+	// entries 返回所有键值对的快照，顺序与 Map 的迭代顺序一致
+	entries() []Entry[K, V]
+
+	// valueComparator 返回值的比较器
+	valueComparator() constraints.EqualComparator[V]
+
+	// peek 与 Get 相同，但不算作一次访问，不会改变按照访问顺序迭代的 Map 的顺序
+	peek(k K) (V, bool)
+}
+
+func newKeySetView[K comparable, V any](m viewableMap[K, V]) Set[K] {
+	return &mapView[K, V, K]{
+		m: m,
+		project: func(e Entry[K, V]) K {
+			return e.Key()
+		},
+		contains: m.ContainsKey,
+		remove: func(k K) bool {
+			_, ok := m.Remove(k)
+			return ok
+		},
+		comparator: comparableEqual[K](),
+	}
+}
+
+func newValuesView[K comparable, V any](m viewableMap[K, V]) Collection[V] {
+	comparator := m.valueComparator()
+	return &mapView[K, V, V]{
+		m: m,
+		project: func(e Entry[K, V]) V {
+			return e.Value()
+		},
+		contains: m.ContainsValue,
+		remove: func(v V) bool {
+			for _, e := range m.entries() {
+				if comparator.Equal(v, e.Value()) {
+					m.Remove(e.Key())
+					return true
+				}
+			}
+			return false
+		},
+		comparator: comparator,
+	}
+}
+
+func newEntrySetView[K comparable, V any](m viewableMap[K, V]) Set[Entry[K, V]] {
+	comparator := m.valueComparator()
+	contains := func(e Entry[K, V]) bool {
+		v, ok := m.peek(e.Key())
+		return ok && comparator.Equal(v, e.Value())
+	}
+	return &mapView[K, V, Entry[K, V]]{
+		m: m,
+		project: func(e Entry[K, V]) Entry[K, V] {
+			return e
+		},
+		contains: contains,
+		remove: func(e Entry[K, V]) bool {
+			if contains(e) {
+				m.Remove(e.Key())
+				return true
+			}
+			return false
+		},
+		comparator: AnyEqualComparableFunc[Entry[K, V]](func(e1, e2 Entry[K, V]) bool {
+			return e1.Key() == e2.Key() && comparator.Equal(e1.Value(), e2.Value())
+		}),
+	}
+}
+
+// mapView Map 的集合视图，E 为视图中的元素类型
+type mapView[K comparable, V any, E any] struct {
+	m          viewableMap[K, V]
+	project    func(e Entry[K, V]) E
+	contains   func(e E) bool
+	remove     func(e E) bool
+	comparator constraints.EqualComparator[E]
+}
+
+func (m *mapView[K, V, E]) Size() int {
+	return m.m.Size()
+}
+
+func (m *mapView[K, V, E]) IsEmpty() bool {
+	return m.m.IsEmpty()
+}
+
+func (m *mapView[K, V, E]) Contains(e E) bool {
+	return m.contains(e)
+}
+
+func (m *mapView[K, V, E]) Iterator() Iterator[E] {
+	return &mapViewIterator[K, V, E]{
+		lastRet: -1,
+		entries: m.m.entries(),
+		view:    m,
+	}
+}
+
+func (m *mapView[K, V, E]) ToArray() []E {
+	entries := m.m.entries()
+	if len(entries) == 0 {
+		return nil
+	}
+	res := make([]E, len(entries))
+	for i, e := range entries {
+		res[i] = m.project(e)
+	}
+	return res
+}
+
+// Add 视图不支持添加元素，返回false
+func (m *mapView[K, V, E]) Add(E) bool {
+	return false
+}
+
+func (m *mapView[K, V, E]) Remove(e E) bool {
+	return m.remove(e)
+}
+
+func (m *mapView[K, V, E]) ContainsAll(c Collection[E]) bool {
+	itr := c.Iterator()
+	for itr.HasNext() {
+		if e, err := itr.Next(); err != nil || !m.contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddAll 视图不支持添加元素，调用不会产生任何效果
+func (m *mapView[K, V, E]) AddAll(Collection[E]) {
+}
+
+func (m *mapView[K, V, E]) RemoveAll(c Collection[E]) int {
+	return m.RemoveIf(func(e E) bool {
+		return c.Contains(e)
+	})
+}
+
+func (m *mapView[K, V, E]) RemoveIf(filter Predicate[E]) int {
+	var cnt int
+	for _, e := range m.m.entries() {
+		if filter(m.project(e)) {
+			if _, ok := m.m.Remove(e.Key()); ok {
+				cnt++
+			}
+		}
+	}
+	return cnt
+}
+
+func (m *mapView[K, V, E]) RetainAll(c Collection[E]) int {
+	return m.RemoveIf(func(e E) bool {
+		return !c.Contains(e)
+	})
+}
+
+func (m *mapView[K, V, E]) Clear() {
+	m.m.Clear()
+}
+
+func (m *mapView[K, V, E]) Equals(c Collection[E]) bool {
+	if Collection[E](m) == c {
+		return true
+	}
+	return m.Size() == c.Size() && m.ContainsAll(c)
+}
+
+func (m *mapView[K, V, E]) ForEach(f Consumer[E]) error {
+	for _, e := range m.m.entries() {
+		if err := f(m.project(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mapView[K, V, E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, e := range m.m.entries() {
+			if !yield(m.project(e)) {
+				return
+			}
+		}
+	}
+}
+
+func (m *mapView[K, V, E]) GetEqualComparator() constraints.EqualComparator[E] {
+	return m.comparator
+}
+
+func (m *mapView[K, V, E]) String() string {
+	return fmt.Sprintf("%v", m.ToArray())
+}
+
+// mapViewIterator 基于键值对快照的视图迭代器，Remove 方法会删除 Map 中对应的键
+type mapViewIterator[K comparable, V any, E any] struct {
+	cursor, lastRet int
+
+	isClose bool
+	entries []Entry[K, V]
+	view    *mapView[K, V, E]
+}
+
+func (m *mapViewIterator[K, V, E]) HasNext() bool {
+	return m.cursor < len(m.entries) && m.isClose == false
+}
+
+func (m *mapViewIterator[K, V, E]) Next() (e E, err error) {
+	if m.isClose {
+		err = ErrIteratorClose
+		return
+	}
+	i := m.cursor
+	if i >= len(m.entries) {
+		err = ErrNoSuchElement
+		return
+	}
+	m.cursor = i + 1
+	m.lastRet = i
+	return m.view.project(m.entries[i]), nil
+}
+
+func (m *mapViewIterator[K, V, E]) Remove() error {
+	if m.isClose {
+		return ErrIteratorClose
+	}
+	if m.lastRet < 0 {
+		return ErrIllegalState
+	}
+	m.view.m.Remove(m.entries[m.lastRet].Key())
+	m.lastRet = -1
+	return nil
+}
+
+func (m *mapViewIterator[K, V, E]) ForEachRemaining(action Consumer[E]) error {
+	if m.isClose {
+		return ErrIteratorClose
+	}
+	var err error
+	for i := m.cursor; i < len(m.entries); i++ {
+		err = action(m.view.project(m.entries[i]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mapViewIterator[K, V, E]) Close() {
+	m.isClose = true
+	m.cursor = math.MaxInt
+}
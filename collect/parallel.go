@@ -0,0 +1,412 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"context"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelForEach 使用最多 parallelism 个 goroutine 并发处理 list 中的元素，parallelism 小于 1 时使用 runtime.GOMAXPROCS(0)
+// list 的元素快照按照索引划分为连续的区间，每个 goroutine 处理一个区间，f 会被并发调用
+// f 返回错误或 ctx 结束时取消剩余的处理并返回第一个错误，ctx 结束时返回 ctx.Err()
+func ParallelForEach[E any](ctx context.Context, list List[E], parallelism int, f Consumer[E]) error {
+	data := list.ToArray()
+	return parallelRun(ctx, len(data), parallelism, func(ctx context.Context, _, lo, hi int) error {
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := f(data[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ParallelStreamOf 创建以 list 为数据源的并行 Stream，parallelism 小于 1 时使用 runtime.GOMAXPROCS(0)
+// Filter、Map、FlatMap、Peek 以及 ForEach、Reduce、AnyMatch、AllMatch、NoneMatch 会在多个 goroutine 中并发执行，
+// 因此传入的函数必须是并发安全的，ForEach、Peek 不保证调用顺序
+// Reduce 的 accumulator 必须满足结合律，identity 只会与合并后的结果计算一次，结果与顺序的 Stream 相同
+// Distinct、Sorted、Limit、Skip、FindFirst、ToList 和 All 会先按照原有顺序合并并发处理的结果再继续处理，
+// 与顺序的 Stream 一样，这些操作在调用终止操作时才会执行，Limit 和 FindFirst 得到足够的元素后停止处理
+func ParallelStreamOf[E any](list List[E], parallelism int) Stream[E] {
+	if parallelism < 1 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	data := list.ToArray()
+	return &parallelStream[E]{
+		source: func() []E {
+			return data
+		},
+		parallelism: parallelism,
+		comparator:  orDefaultComparator(list.GetEqualComparator()),
+	}
+}
+
+// parallelRun 将区间 [0, n) 划分为最多 parallelism 个连续的子区间，每个子区间在独立的 goroutine 中调用 f
+// 参数 chunk 为子区间的序号，任意一个 f 返回错误时取消 ctx 并返回第一个错误
+func parallelRun(ctx context.Context, n, parallelism int, f func(ctx context.Context, chunk, lo, hi int) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	chunks := min(parallelism, n)
+	if chunks <= 1 {
+		return f(ctx, 0, 0, n)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i := 0; i < chunks; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := f(ctx, i, i*n/chunks, (i+1)*n/chunks); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+type parallelStream[E any] struct {
+	// source 返回数据源的元素，Distinct、Sorted 等有状态的中间操作在终止操作调用 source 时才会合并上游的结果
+	source      func() []E
+	parallelism int
+	comparator  constraints.EqualComparator[E]
+
+	// stage 组合后的无状态中间操作，将元素 e 转换后交给 emit，emit 返回 false 时停止并返回 false
+	// 为 nil 时表示元素原样输出
+	stage func(e E, emit func(E) bool) bool
+}
+
+// then 在当前的无状态中间操作之后追加一个操作
+func (p *parallelStream[E]) then(next func(e E, emit func(E) bool) bool) Stream[E] {
+	prev := p.stage
+	stage := next
+	if prev != nil {
+		stage = func(e E, emit func(E) bool) bool {
+			return prev(e, func(r E) bool {
+				return next(r, emit)
+			})
+		}
+	}
+	return &parallelStream[E]{
+		source:      p.source,
+		parallelism: p.parallelism,
+		comparator:  p.comparator,
+		stage:       stage,
+	}
+}
+
+// barrier 创建以 source 为数据源、没有中间操作的并行 Stream，用于实现有状态的中间操作
+func (p *parallelStream[E]) barrier(source func() []E) Stream[E] {
+	return &parallelStream[E]{
+		source:      source,
+		parallelism: p.parallelism,
+		comparator:  p.comparator,
+	}
+}
+
+// apply 将中间操作依次应用到 values 中的元素上，emit 返回 false 或者 ctx 结束时停止
+func (p *parallelStream[E]) apply(ctx context.Context, values []E, emit func(E) bool) {
+	out := func(e E) bool {
+		return ctx.Err() == nil && emit(e)
+	}
+	for _, e := range values {
+		if ctx.Err() != nil {
+			return
+		}
+		if p.stage == nil {
+			if !out(e) {
+				return
+			}
+		} else if !p.stage(e, out) {
+			return
+		}
+	}
+}
+
+// run 并发地将中间操作应用到 data 的每个元素上，emit 以子区间序号和转换后的元素调用
+// emit 返回 false 时结束当前子区间的处理，需要结束所有子区间时取消 ctx
+func (p *parallelStream[E]) run(ctx context.Context, data []E, emit func(chunk int, e E) bool) {
+	_ = parallelRun(ctx, len(data), p.parallelism, func(ctx context.Context, chunk, lo, hi int) error {
+		p.apply(ctx, data[lo:hi], func(e E) bool {
+			return emit(chunk, e)
+		})
+		return nil
+	})
+}
+
+// collect 并发执行中间操作，按照原有顺序合并结果
+func (p *parallelStream[E]) collect() []E {
+	data := p.source()
+	if p.stage == nil {
+		return data
+	}
+	parts := make([][]E, min(p.parallelism, len(data)))
+	p.run(context.Background(), data, func(chunk int, e E) bool {
+		parts[chunk] = append(parts[chunk], e)
+		return true
+	})
+	return slices.Concat(parts...)
+}
+
+// collectN 并发执行中间操作，按照原有顺序返回前 n 个结果
+// 每个子区间最多处理出 n 个结果，排在前面的子区间的结果已经足够 n 个时结束所有子区间的处理
+func (p *parallelStream[E]) collectN(n int) []E {
+	if n <= 0 {
+		return nil
+	}
+	data := p.source()
+	if p.stage == nil {
+		return data[:min(n, len(data))]
+	}
+	chunks := min(p.parallelism, len(data))
+	if chunks == 0 {
+		return nil
+	}
+	var mu sync.Mutex
+	parts := make([][]E, chunks)
+	finished := make([]bool, chunks)
+	// enough 调用时需要持有 mu
+	enough := func() bool {
+		cnt := 0
+		for i, part := range parts {
+			if cnt += len(part); cnt >= n {
+				return true
+			} else if !finished[i] {
+				return false
+			}
+		}
+		return true
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_ = parallelRun(ctx, len(data), p.parallelism, func(ctx context.Context, chunk, lo, hi int) error {
+		p.apply(ctx, data[lo:hi], func(e E) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			parts[chunk] = append(parts[chunk], e)
+			if enough() {
+				cancel()
+				return false
+			}
+			return len(parts[chunk]) < n
+		})
+		mu.Lock()
+		finished[chunk] = true
+		if enough() {
+			cancel()
+		}
+		mu.Unlock()
+		return nil
+	})
+	res := slices.Concat(parts...)
+	return res[:min(n, len(res))]
+}
+
+func (p *parallelStream[E]) sequential() Stream[E] {
+	return &stream[E]{
+		seq: func(yield func(E) bool) {
+			for _, e := range p.collect() {
+				if !yield(e) {
+					return
+				}
+			}
+		},
+		comparator: p.comparator,
+	}
+}
+
+func (p *parallelStream[E]) Filter(predicate Predicate[E]) Stream[E] {
+	return p.then(func(e E, emit func(E) bool) bool {
+		return !predicate(e) || emit(e)
+	})
+}
+
+func (p *parallelStream[E]) Map(mapper UnaryOperator[E]) Stream[E] {
+	return p.then(func(e E, emit func(E) bool) bool {
+		return emit(mapper(e))
+	})
+}
+
+func (p *parallelStream[E]) FlatMap(mapper func(e E) Stream[E]) Stream[E] {
+	return p.then(func(e E, emit func(E) bool) bool {
+		for r := range mapper(e).All() {
+			if !emit(r) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (p *parallelStream[E]) Distinct() Stream[E] {
+	return p.barrier(func() []E {
+		return p.sequential().Distinct().ToList().ToArray()
+	})
+}
+
+func (p *parallelStream[E]) Sorted(less SortLess[E]) Stream[E] {
+	return p.barrier(func() []E {
+		data := slices.Clone(p.collect())
+		slices.SortStableFunc(data, func(a, b E) int {
+			if less(a, b) {
+				return -1
+			} else if less(b, a) {
+				return 1
+			}
+			return 0
+		})
+		return data
+	})
+}
+
+func (p *parallelStream[E]) Limit(n int) Stream[E] {
+	return p.barrier(func() []E {
+		return p.collectN(n)
+	})
+}
+
+func (p *parallelStream[E]) Skip(n int) Stream[E] {
+	return p.barrier(func() []E {
+		data := p.collect()
+		return data[max(0, min(n, len(data))):]
+	})
+}
+
+func (p *parallelStream[E]) Peek(action CallBack[E]) Stream[E] {
+	return p.then(func(e E, emit func(E) bool) bool {
+		action(e)
+		return emit(e)
+	})
+}
+
+func (p *parallelStream[E]) ForEach(f Consumer[E]) error {
+	var (
+		once     sync.Once
+		firstErr error
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.run(ctx, p.source(), func(_ int, e E) bool {
+		if err := f(e); err != nil {
+			once.Do(func() {
+				firstErr = err
+				cancel()
+			})
+			return false
+		}
+		return true
+	})
+	return firstErr
+}
+func (p *parallelStream[E]) Reduce(identity E, accumulator func(e1, e2 E) E) E {
+	data := p.source()
+	// 每个子区间的结果以第一个元素为初始值，没有元素的子区间不参与合并
+	parts := make([]E, min(p.parallelism, len(data)))
+	seeded := make([]bool, len(parts))
+	p.run(context.Background(), data, func(chunk int, e E) bool {
+		if seeded[chunk] {
+			parts[chunk] = accumulator(parts[chunk], e)
+		} else {
+			parts[chunk], seeded[chunk] = e, true
+		}
+		return true
+	})
+	res := identity
+	for i, e := range parts {
+		if seeded[i] {
+			res = accumulator(res, e)
+		}
+	}
+	return res
+}
+
+func (p *parallelStream[E]) Count() int {
+	data := p.source()
+	if p.stage == nil {
+		return len(data)
+	}
+	var cnt atomic.Int64
+	p.run(context.Background(), data, func(int, E) bool {
+		cnt.Add(1)
+		return true
+	})
+	return int(cnt.Load())
+}
+
+func (p *parallelStream[E]) AnyMatch(predicate Predicate[E]) bool {
+	var found atomic.Bool
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.run(ctx, p.source(), func(_ int, e E) bool {
+		if predicate(e) {
+			found.Store(true)
+			cancel()
+			return false
+		}
+		return true
+	})
+	return found.Load()
+}
+
+func (p *parallelStream[E]) AllMatch(predicate Predicate[E]) bool {
+	return !p.AnyMatch(func(e E) bool {
+		return !predicate(e)
+	})
+}
+
+func (p *parallelStream[E]) NoneMatch(predicate Predicate[E]) bool {
+	return !p.AnyMatch(predicate)
+}
+
+func (p *parallelStream[E]) FindFirst() (E, bool) {
+	if res := p.collectN(1); len(res) > 0 {
+		return res[0], true
+	}
+	var zero E
+	return zero, false
+}
+
+func (p *parallelStream[E]) ToList() List[E] {
+	return p.sequential().ToList()
+}
+
+func (p *parallelStream[E]) All() iter.Seq[E] {
+	return p.sequential().All()
+}
@@ -0,0 +1,156 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_ParallelForEach(t *testing.T) {
+	for _, c := range configList {
+		list := NewList[int](c)
+		for i := 1; i <= 1000; i++ {
+			list.Add(i)
+		}
+		var sum atomic.Int64
+		err := ParallelForEach(context.Background(), list, 4, func(e int) error {
+			sum.Add(int64(e))
+			return nil
+		})
+		if err != nil || sum.Load() != 500500 {
+			t.Errorf("ParallelForEach() = %v, sum %v, want nil, 500500", err, sum.Load())
+		}
+	}
+}
+
+func Test_ParallelForEach_error(t *testing.T) {
+	list := NewList[int](DefaultListConfig)
+	for i := 0; i < 10000; i++ {
+		list.Add(i)
+	}
+	errBad := errors.New("bad element")
+	var cnt atomic.Int64
+	err := ParallelForEach(context.Background(), list, 4, func(e int) error {
+		cnt.Add(1)
+		if e == 10 {
+			return errBad
+		}
+		return nil
+	})
+	if !errors.Is(err, errBad) {
+		t.Errorf("ParallelForEach() = %v, want %v", err, errBad)
+	}
+	if cnt.Load() == 10000 {
+		t.Error("ParallelForEach() did not cancel the remaining work")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = ParallelForEach(ctx, list, 4, func(e int) error {
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ParallelForEach() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func Test_parallelStream(t *testing.T) {
+	list := NewList[int](DefaultListConfig)
+	for i := 0; i < 100; i++ {
+		list.Add(i)
+	}
+	s := ParallelStreamOf(list, 4).
+		Filter(func(e int) bool { return e%3 == 0 }).
+		Map(func(e int) int { return e / 3 })
+	if got := s.Limit(5).ToList().ToArray(); !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("ToList() = %v, want %v", got, []int{0, 1, 2, 3, 4})
+	}
+	if n := s.Count(); n != 34 {
+		t.Errorf("Count() = %v, want 34", n)
+	}
+	if sum := s.Reduce(0, func(e1, e2 int) int { return e1 + e2 }); sum != 561 {
+		t.Errorf("Reduce() = %v, want 561", sum)
+	}
+	// identity 不是单位元时与顺序的 Stream 结果相同
+	if sum, want := s.Reduce(100, func(e1, e2 int) int { return e1 + e2 }), StreamOf(s.ToList()).Reduce(100, func(e1, e2 int) int { return e1 + e2 }); sum != want {
+		t.Errorf("Reduce() = %v, want %v", sum, want)
+	}
+	if !s.AnyMatch(func(e int) bool { return e == 33 }) || s.AllMatch(func(e int) bool { return e < 33 }) {
+		t.Error("AnyMatch()/AllMatch() mismatch")
+	}
+	got := s.Map(func(e int) int { return e % 4 }).Distinct().Sorted(SortLessOrdered[int](false)).Skip(1).ToList().ToArray()
+	if !reflect.DeepEqual(got, []int{2, 1, 0}) {
+		t.Errorf("Distinct().Sorted() = %v, want %v", got, []int{2, 1, 0})
+	}
+	if e, ok := s.Skip(10).FindFirst(); !ok || e != 10 {
+		t.Errorf("FindFirst() = %v, %v, want 10, true", e, ok)
+	}
+	var peeked atomic.Int64
+	err := s.Peek(func(e int) { peeked.Add(1) }).FlatMap(func(e int) Stream[int] {
+		return StreamOfSeq(func(yield func(int) bool) {
+			_ = yield(e) && yield(e)
+		})
+	}).ForEach(func(e int) error {
+		return nil
+	})
+	if err != nil || peeked.Load() != 34 {
+		t.Errorf("ForEach() = %v, peeked %d, want nil, 34", err, peeked.Load())
+	}
+	if n := ParallelStreamOf(NewList[int](DefaultListConfig), 0).Count(); n != 0 {
+		t.Errorf("Count() = %v, want 0", n)
+	}
+	// 比较器为 nil 的 List 使用默认的比较器
+	nilComparator := NewArrayList[int](0, nil)
+	nilComparator.AddAll(wrapArrayList([]int{1, 2, 1, 3, 2}))
+	if n := ParallelStreamOf(nilComparator, 2).Distinct().Count(); n != 3 {
+		t.Errorf("Distinct().Count() = %v, want 3", n)
+	}
+}
+
+func Test_parallelStream_lazy(t *testing.T) {
+	list := NewList[int](DefaultListConfig)
+	for i := 0; i < 100; i++ {
+		list.Add(i)
+	}
+	for _, parallelism := range []int{1, 4} {
+		var peeked atomic.Int64
+		s := ParallelStreamOf(list, parallelism).Peek(func(int) { peeked.Add(1) })
+		limited := s.Limit(2).Sorted(SortLessOrdered[int](false)).Skip(0).Distinct()
+		if n := peeked.Load(); n != 0 {
+			t.Errorf("parallelism %d: peeked %d before terminal operation, want 0", parallelism, n)
+		}
+		if got := limited.ToList().ToArray(); !reflect.DeepEqual(got, []int{1, 0}) {
+			t.Errorf("parallelism %d: ToList() = %v, want %v", parallelism, got, []int{1, 0})
+		}
+		// 每个子区间最多处理 2 个元素
+		if n := peeked.Load(); n > int64(2*parallelism) {
+			t.Errorf("parallelism %d: peeked %d, want <= %d", parallelism, n, 2*parallelism)
+		}
+		peeked.Store(0)
+		if e, ok := s.Filter(func(e int) bool { return e >= 10 }).FindFirst(); !ok || e != 10 {
+			t.Errorf("parallelism %d: FindFirst() = %v, %v, want 10, true", parallelism, e, ok)
+		}
+		if parallelism == 1 && peeked.Load() != 11 {
+			t.Errorf("FindFirst() peeked %d, want 11", peeked.Load())
+		}
+	}
+}
@@ -0,0 +1,316 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+)
+
+// PriorityQueue 基于二叉堆的优先队列，队列头部为按照 SortLess 排序后的第一个元素
+// 迭代器、ToArray 和 ForEach 的顺序为堆中的存储顺序，不保证有序
+type PriorityQueue[E comparable] interface {
+	Queue[E]
+
+	// Update 元素的排序依据发生变化后调用，重新调整该元素在堆中的位置，时间复杂度为 O(log n)
+	// 元素不存在返回false
+	Update(e E) bool
+}
+
+// NewPriorityQueue 创建一个使用 less 排序的优先队列
+func NewPriorityQueue[E comparable](less SortLess[E]) PriorityQueue[E] {
+	return &priorityQueue[E]{
+		less:  less,
+		index: make(map[E][]*heapItem[E]),
+	}
+}
+
+// NewOrderedPriorityQueue 创建一个基础类型的优先队列
+// 参数 asc 表示是否为升序，升序时队列头部为最小的元素
+func NewOrderedPriorityQueue[E constraints.Ordered](asc bool) PriorityQueue[E] {
+	return NewPriorityQueue[E](SortLessOrdered[E](asc))
+}
+
+// NewComparablePriorityQueue 创建一个实现了 constraints.Comparable 接口的元素的优先队列
+// 参数 asc 表示是否为升序，升序时队列头部为最小的元素
+func NewComparablePriorityQueue[E interface {
+	comparable
+	constraints.Comparable[E]
+}](asc bool) PriorityQueue[E] {
+	return NewPriorityQueue[E](SortLessComparable[E](asc))
+}
+
+type heapItem[E any] struct {
+	value E
+	// index 元素在堆中的下标
+	index int
+}
+
+type priorityQueue[E comparable] struct {
+	heap []*heapItem[E]
+	// index 记录元素对应的堆节点，用于 O(log n) 的 Remove 和 Update
+	index   map[E][]*heapItem[E]
+	less    SortLess[E]
+	zeroVal E
+}
+
+func (p *priorityQueue[E]) Size() int {
+	return len(p.heap)
+}
+
+func (p *priorityQueue[E]) IsEmpty() bool {
+	return len(p.heap) == 0
+}
+
+func (p *priorityQueue[E]) Contains(e E) bool {
+	return len(p.index[e]) > 0
+}
+
+func (p *priorityQueue[E]) Iterator() Iterator[E] {
+	return newSnapshotIterator[E](p)
+}
+
+func (p *priorityQueue[E]) ToArray() []E {
+	if len(p.heap) == 0 {
+		return nil
+	}
+	res := make([]E, len(p.heap))
+	for i, item := range p.heap {
+		res[i] = item.value
+	}
+	return res
+}
+
+func (p *priorityQueue[E]) Add(e E) bool {
+	return p.Put(e)
+}
+
+func (p *priorityQueue[E]) Remove(e E) bool {
+	items := p.index[e]
+	if len(items) == 0 {
+		return false
+	}
+	p.removeAt(items[len(items)-1].index)
+	return true
+}
+
+func (p *priorityQueue[E]) ContainsAll(c Collection[E]) bool {
+	itr := c.Iterator()
+	for itr.HasNext() {
+		if e, err := itr.Next(); err != nil || !p.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *priorityQueue[E]) AddAll(c Collection[E]) {
+	_ = c.ForEach(func(e E) error {
+		p.Put(e)
+		return nil
+	})
+}
+
+func (p *priorityQueue[E]) RemoveAll(c Collection[E]) int {
+	return p.RemoveIf(func(e E) bool {
+		return c.Contains(e)
+	})
+}
+
+func (p *priorityQueue[E]) RemoveIf(filter Predicate[E]) int {
+	var kept int
+	for _, item := range p.heap {
+		if filter(item.value) {
+			p.unindex(item)
+		} else {
+			item.index = kept
+			p.heap[kept] = item
+			kept++
+		}
+	}
+	cnt := len(p.heap) - kept
+	if cnt == 0 {
+		return 0
+	}
+	clear(p.heap[kept:])
+	p.heap = p.heap[:kept]
+	p.heapify()
+	return cnt
+}
+
+func (p *priorityQueue[E]) RetainAll(c Collection[E]) int {
+	return p.RemoveIf(func(e E) bool {
+		return !c.Contains(e)
+	})
+}
+
+func (p *priorityQueue[E]) Clear() {
+	p.heap = nil
+	p.index = make(map[E][]*heapItem[E])
+}
+
+func (p *priorityQueue[E]) Equals(c Collection[E]) bool {
+	return equals[E](p, c)
+}
+
+func (p *priorityQueue[E]) ForEach(f Consumer[E]) error {
+	for _, item := range p.heap {
+		if err := f(item.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *priorityQueue[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, item := range p.heap {
+			if !yield(item.value) {
+				return
+			}
+		}
+	}
+}
+
+func (p *priorityQueue[E]) GetEqualComparator() constraints.EqualComparator[E] {
+	return comparableEqual[E]()
+}
+
+func (p *priorityQueue[E]) Put(e E) bool {
+	item := &heapItem[E]{value: e, index: len(p.heap)}
+	p.heap = append(p.heap, item)
+	p.index[e] = append(p.index[e], item)
+	p.up(item.index)
+	return true
+}
+
+func (p *priorityQueue[E]) Take() (E, bool) {
+	if len(p.heap) == 0 {
+		return p.zeroVal, false
+	}
+	e := p.heap[0].value
+	p.removeAt(0)
+	return e, true
+}
+
+func (p *priorityQueue[E]) Peek() (E, bool) {
+	if len(p.heap) == 0 {
+		return p.zeroVal, false
+	}
+	return p.heap[0].value, true
+}
+
+func (p *priorityQueue[E]) Update(e E) bool {
+	items := p.index[e]
+	if len(items) == 0 {
+		return false
+	}
+	for _, item := range items {
+		p.fix(item.index)
+	}
+	return true
+}
+
+func (p *priorityQueue[E]) String() string {
+	return fmt.Sprintf("%v", p.ToArray())
+}
+
+// removeAt 删除堆中指定下标的元素
+func (p *priorityQueue[E]) removeAt(i int) {
+	item := p.heap[i]
+	last := len(p.heap) - 1
+	if i != last {
+		p.swap(i, last)
+	}
+	p.heap[last] = nil
+	p.heap = p.heap[:last]
+	if i != last {
+		p.fix(i)
+	}
+	p.unindex(item)
+}
+
+// unindex 从索引中删除堆节点
+func (p *priorityQueue[E]) unindex(item *heapItem[E]) {
+	items := p.index[item.value]
+	for i, v := range items {
+		if v == item {
+			items[i] = items[len(items)-1]
+			items[len(items)-1] = nil
+			items = items[:len(items)-1]
+			break
+		}
+	}
+	if len(items) == 0 {
+		delete(p.index, item.value)
+	} else {
+		p.index[item.value] = items
+	}
+}
+
+func (p *priorityQueue[E]) heapify() {
+	for i := len(p.heap)/2 - 1; i >= 0; i-- {
+		p.down(i)
+	}
+}
+
+// fix 元素发生变化后重新调整元素位置
+func (p *priorityQueue[E]) fix(i int) {
+	if !p.down(i) {
+		p.up(i)
+	}
+}
+
+func (p *priorityQueue[E]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !p.less(p.heap[i].value, p.heap[parent].value) {
+			break
+		}
+		p.swap(i, parent)
+		i = parent
+	}
+}
+
+// down 将元素向下调整，元素位置发生变化返回true
+func (p *priorityQueue[E]) down(i int) bool {
+	start, n := i, len(p.heap)
+	for {
+		child := 2*i + 1
+		if child >= n || child < 0 {
+			break
+		}
+		if right := child + 1; right < n && p.less(p.heap[right].value, p.heap[child].value) {
+			child = right
+		}
+		if !p.less(p.heap[child].value, p.heap[i].value) {
+			break
+		}
+		p.swap(i, child)
+		i = child
+	}
+	return i > start
+}
+
+func (p *priorityQueue[E]) swap(i, j int) {
+	p.heap[i], p.heap[j] = p.heap[j], p.heap[i]
+	p.heap[i].index = i
+	p.heap[j].index = j
+}
@@ -0,0 +1,136 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type priorityTask struct {
+	name     string
+	priority int
+}
+
+func (t *priorityTask) Compare(v *priorityTask) int {
+	if t.priority == v.priority {
+		return 0
+	} else if t.priority > v.priority {
+		return 1
+	}
+	return -1
+}
+
+func takeAll[E comparable](q Queue[E]) []E {
+	var res []E
+	for {
+		e, ok := q.Take()
+		if !ok {
+			return res
+		}
+		res = append(res, e)
+	}
+}
+
+func Test_priorityQueue_order(t *testing.T) {
+	s := []int{5, 1, 9, 3, 3, 7, 2, 8}
+	asc := NewOrderedPriorityQueue[int](true)
+	desc := NewOrderedPriorityQueue[int](false)
+	for _, v := range s {
+		asc.Put(v)
+		desc.Put(v)
+	}
+	if e, ok := asc.Peek(); !ok || e != 1 {
+		t.Errorf("Peek() = %v, %v, want 1, true", e, ok)
+	}
+	want := slices.Clone(s)
+	slices.Sort(want)
+	if got := takeAll[int](asc); !reflect.DeepEqual(got, want) {
+		t.Errorf("Take() = %v, want %v", got, want)
+	}
+	slices.Reverse(want)
+	if got := takeAll[int](desc); !reflect.DeepEqual(got, want) {
+		t.Errorf("Take() = %v, want %v", got, want)
+	}
+	if _, ok := asc.Peek(); ok {
+		t.Error("Peek() ok = true, want false")
+	}
+}
+
+func Test_priorityQueue_remove(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	q := NewOrderedPriorityQueue[int](true)
+	var want []int
+	for i := 0; i < 200; i++ {
+		v := r.Intn(50)
+		q.Put(v)
+		want = append(want, v)
+	}
+	for i := 0; i < 50; i++ {
+		v := r.Intn(60)
+		idx := slices.Index(want, v)
+		if got := q.Remove(v); got != (idx >= 0) {
+			t.Errorf("Remove(%d) = %v, want %v", v, got, idx >= 0)
+		}
+		if idx >= 0 {
+			want = slices.Delete(want, idx, idx+1)
+		}
+	}
+	size := len(want)
+	want = slices.DeleteFunc(want, func(e int) bool { return e%3 == 0 })
+	if n := q.RemoveIf(func(e int) bool { return e%3 == 0 }); n != size-len(want) {
+		t.Errorf("RemoveIf() = %d, want %d", n, size-len(want))
+	}
+	if q.Contains(3) {
+		t.Error("Contains(3) = true, want false")
+	}
+	slices.Sort(want)
+	if got := takeAll[int](q); !reflect.DeepEqual(got, want) {
+		t.Errorf("Take() = %v, want %v", got, want)
+	}
+}
+
+func Test_priorityQueue_update(t *testing.T) {
+	tasks := []*priorityTask{{"a", 5}, {"b", 3}, {"c", 8}, {"d", 1}}
+	q := NewComparablePriorityQueue[*priorityTask](true)
+	for _, task := range tasks {
+		q.Put(task)
+	}
+	if e, _ := q.Peek(); e.name != "d" {
+		t.Errorf("Peek() = %v, want d", e.name)
+	}
+	tasks[2].priority = 0
+	if !q.Update(tasks[2]) {
+		t.Error("Update() = false, want true")
+	}
+	tasks[3].priority = 10
+	q.Update(tasks[3])
+	if q.Update(&priorityTask{"e", 1}) {
+		t.Error("Update() = true, want false")
+	}
+	var names []string
+	for _, task := range takeAll[*priorityTask](q) {
+		names = append(names, task.name)
+	}
+	if want := []string{"c", "b", "a", "d"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Take() = %v, want %v", names, want)
+	}
+}
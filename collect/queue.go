@@ -18,6 +18,11 @@
 
 package collect
 
+import (
+	"context"
+	"time"
+)
+
 type Queue[E comparable] interface {
 	Collection[E]
 
@@ -32,3 +37,54 @@ type Queue[E comparable] interface {
 	// 如果队列为空，第二个返回值为 false
 	Peek() (E, bool)
 }
+
+// BlockingQueue 支持阻塞等待的队列，所有方法都是并发安全的
+type BlockingQueue[E comparable] interface {
+	Queue[E]
+
+	// PutCtx 将元素插入队列，如果队列已满则阻塞等待，直到有可用空间或 ctx 结束
+	// ctx 结束时返回 ctx.Err()
+	PutCtx(ctx context.Context, e E) error
+
+	// TakeCtx 获取并移除该队列的头部，如果队列为空则阻塞等待，直到有可用元素或 ctx 结束
+	// ctx 结束时返回 ctx.Err()
+	TakeCtx(ctx context.Context) (E, error)
+
+	// Offer 将元素插入队列，如果队列已满最多等待 timeout 时长
+	// 插入成功返回true，超时返回false
+	Offer(e E, timeout time.Duration) bool
+
+	// Poll 获取并移除该队列的头部，如果队列为空最多等待 timeout 时长
+	// 超时第二个返回值为 false
+	Poll(timeout time.Duration) (E, bool)
+
+	// RemainingCapacity 返回队列剩余可用容量
+	RemainingCapacity() int
+
+	// DrainTo 从队列中移除最多 max 个元素，并通过一次 AddAll 调用批量加入到集合 c 中
+	// 参数 max 表示最多移除个数, -1表示全部移除
+	// 返回移除的元素个数
+	DrainTo(c Collection[E], max int) int
+}
+
+// notifier 可以配合 context 使用的条件变量
+// 所有方法都必须在持有对应的锁时调用
+type notifier struct {
+	ch chan struct{}
+}
+
+// wait 返回一个通道，下一次调用 broadcast 时通道会被关闭
+func (n *notifier) wait() <-chan struct{} {
+	if n.ch == nil {
+		n.ch = make(chan struct{})
+	}
+	return n.ch
+}
+
+// broadcast 唤醒所有等待者
+func (n *notifier) broadcast() {
+	if n.ch != nil {
+		close(n.ch)
+		n.ch = nil
+	}
+}
@@ -0,0 +1,382 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import "fmt"
+
+type treeNode[K comparable, V any] struct {
+	key                 K
+	value               V
+	left, right, parent *treeNode[K, V]
+	red                 bool
+}
+
+func (n *treeNode[K, V]) Key() K {
+	return n.key
+}
+
+func (n *treeNode[K, V]) Value() V {
+	return n.value
+}
+
+func (n *treeNode[K, V]) SetValue(v V) V {
+	old := n.value
+	n.value = v
+	return old
+}
+
+func (n *treeNode[K, V]) String() string {
+	return fmt.Sprintf("%v=%v", n.key, n.value)
+}
+
+func newRBTree[K comparable, V any](less SortLess[K]) *rbTree[K, V] {
+	sentinel := &treeNode[K, V]{}
+	return &rbTree[K, V]{
+		root:     sentinel,
+		sentinel: sentinel,
+		less:     less,
+	}
+}
+
+// rbTree 红黑树，节点的顺序由 less 决定
+// 所有叶子节点都指向同一个黑色的哨兵节点 sentinel，对外的方法使用 nil 表示不存在的节点
+// 删除节点时只移动节点而不复制键值，因此已经获取到的节点在树发生变化后依然有效
+type rbTree[K comparable, V any] struct {
+	root, sentinel *treeNode[K, V]
+	size           int
+	less           SortLess[K]
+}
+
+// find 查找键对应的节点，不存在返回 nil
+func (t *rbTree[K, V]) find(k K) *treeNode[K, V] {
+	x := t.root
+	for x != t.sentinel {
+		if t.less(k, x.key) {
+			x = x.left
+		} else if t.less(x.key, k) {
+			x = x.right
+		} else {
+			return x
+		}
+	}
+	return nil
+}
+
+// insert 插入键值对，如果键已经存在返回已存在的节点和 true，不会修改节点的值
+func (t *rbTree[K, V]) insert(k K, v V) (*treeNode[K, V], bool) {
+	y, x := t.sentinel, t.root
+	for x != t.sentinel {
+		y = x
+		if t.less(k, x.key) {
+			x = x.left
+		} else if t.less(x.key, k) {
+			x = x.right
+		} else {
+			return x, true
+		}
+	}
+	z := &treeNode[K, V]{
+		key:    k,
+		value:  v,
+		left:   t.sentinel,
+		right:  t.sentinel,
+		parent: y,
+		red:    true,
+	}
+	if y == t.sentinel {
+		t.root = z
+	} else if t.less(k, y.key) {
+		y.left = z
+	} else {
+		y.right = z
+	}
+	t.insertFixup(z)
+	t.size++
+	return z, false
+}
+
+// delete 删除节点 z
+func (t *rbTree[K, V]) delete(z *treeNode[K, V]) {
+	var x *treeNode[K, V]
+	y := z
+	yRed := y.red
+	if z.left == t.sentinel {
+		x = z.right
+		t.transplant(z, z.right)
+	} else if z.right == t.sentinel {
+		x = z.left
+		t.transplant(z, z.left)
+	} else {
+		y = t.minimum(z.right)
+		yRed = y.red
+		x = y.right
+		if y.parent == z {
+			x.parent = y
+		} else {
+			t.transplant(y, y.right)
+			y.right = z.right
+			y.right.parent = y
+		}
+		t.transplant(z, y)
+		y.left = z.left
+		y.left.parent = y
+		y.red = z.red
+	}
+	if !yRed {
+		t.deleteFixup(x)
+	}
+	t.size--
+}
+
+func (t *rbTree[K, V]) clear() {
+	t.root = t.sentinel
+	t.size = 0
+}
+
+// first 返回最小的节点，树为空返回 nil
+func (t *rbTree[K, V]) first() *treeNode[K, V] {
+	if t.root == t.sentinel {
+		return nil
+	}
+	return t.minimum(t.root)
+}
+
+// last 返回最大的节点，树为空返回 nil
+func (t *rbTree[K, V]) last() *treeNode[K, V] {
+	if t.root == t.sentinel {
+		return nil
+	}
+	return t.maximum(t.root)
+}
+
+// ceiling 返回大于等于 k 的最小节点，inclusive 为 false 时返回大于 k 的最小节点，不存在返回 nil
+func (t *rbTree[K, V]) ceiling(k K, inclusive bool) *treeNode[K, V] {
+	var best *treeNode[K, V]
+	x := t.root
+	for x != t.sentinel {
+		if t.less(x.key, k) || (!inclusive && !t.less(k, x.key)) {
+			x = x.right
+		} else {
+			best = x
+			x = x.left
+		}
+	}
+	return best
+}
+
+// floor 返回小于等于 k 的最大节点，inclusive 为 false 时返回小于 k 的最大节点，不存在返回 nil
+func (t *rbTree[K, V]) floor(k K, inclusive bool) *treeNode[K, V] {
+	var best *treeNode[K, V]
+	x := t.root
+	for x != t.sentinel {
+		if t.less(k, x.key) || (!inclusive && !t.less(x.key, k)) {
+			x = x.left
+		} else {
+			best = x
+			x = x.right
+		}
+	}
+	return best
+}
+
+// successor 返回 x 的后继节点，不存在返回 nil
+func (t *rbTree[K, V]) successor(x *treeNode[K, V]) *treeNode[K, V] {
+	if x.right != t.sentinel {
+		return t.minimum(x.right)
+	}
+	y := x.parent
+	for y != t.sentinel && x == y.right {
+		x, y = y, y.parent
+	}
+	if y == t.sentinel {
+		return nil
+	}
+	return y
+}
+
+// predecessor 返回 x 的前驱节点，不存在返回 nil
+func (t *rbTree[K, V]) predecessor(x *treeNode[K, V]) *treeNode[K, V] {
+	if x.left != t.sentinel {
+		return t.maximum(x.left)
+	}
+	y := x.parent
+	for y != t.sentinel && x == y.left {
+		x, y = y, y.parent
+	}
+	if y == t.sentinel {
+		return nil
+	}
+	return y
+}
+
+func (t *rbTree[K, V]) minimum(x *treeNode[K, V]) *treeNode[K, V] {
+	for x.left != t.sentinel {
+		x = x.left
+	}
+	return x
+}
+
+func (t *rbTree[K, V]) maximum(x *treeNode[K, V]) *treeNode[K, V] {
+	for x.right != t.sentinel {
+		x = x.right
+	}
+	return x
+}
+
+func (t *rbTree[K, V]) insertFixup(z *treeNode[K, V]) {
+	for z.parent.red {
+		if z.parent == z.parent.parent.left {
+			y := z.parent.parent.right
+			if y.red {
+				z.parent.red = false
+				y.red = false
+				z.parent.parent.red = true
+				z = z.parent.parent
+			} else {
+				if z == z.parent.right {
+					z = z.parent
+					t.leftRotate(z)
+				}
+				z.parent.red = false
+				z.parent.parent.red = true
+				t.rightRotate(z.parent.parent)
+			}
+		} else {
+			y := z.parent.parent.left
+			if y.red {
+				z.parent.red = false
+				y.red = false
+				z.parent.parent.red = true
+				z = z.parent.parent
+			} else {
+				if z == z.parent.left {
+					z = z.parent
+					t.rightRotate(z)
+				}
+				z.parent.red = false
+				z.parent.parent.red = true
+				t.leftRotate(z.parent.parent)
+			}
+		}
+	}
+	t.root.red = false
+}
+
+func (t *rbTree[K, V]) deleteFixup(x *treeNode[K, V]) {
+	for x != t.root && !x.red {
+		if x == x.parent.left {
+			w := x.parent.right
+			if w.red {
+				w.red = false
+				x.parent.red = true
+				t.leftRotate(x.parent)
+				w = x.parent.right
+			}
+			if !w.left.red && !w.right.red {
+				w.red = true
+				x = x.parent
+			} else {
+				if !w.right.red {
+					w.left.red = false
+					w.red = true
+					t.rightRotate(w)
+					w = x.parent.right
+				}
+				w.red = x.parent.red
+				x.parent.red = false
+				w.right.red = false
+				t.leftRotate(x.parent)
+				x = t.root
+			}
+		} else {
+			w := x.parent.left
+			if w.red {
+				w.red = false
+				x.parent.red = true
+				t.rightRotate(x.parent)
+				w = x.parent.left
+			}
+			if !w.right.red && !w.left.red {
+				w.red = true
+				x = x.parent
+			} else {
+				if !w.left.red {
+					w.right.red = false
+					w.red = true
+					t.leftRotate(w)
+					w = x.parent.left
+				}
+				w.red = x.parent.red
+				x.parent.red = false
+				w.left.red = false
+				t.rightRotate(x.parent)
+				x = t.root
+			}
+		}
+	}
+	x.red = false
+}
+
+// transplant 使用子树 v 替换子树 u
+func (t *rbTree[K, V]) transplant(u, v *treeNode[K, V]) {
+	if u.parent == t.sentinel {
+		t.root = v
+	} else if u == u.parent.left {
+		u.parent.left = v
+	} else {
+		u.parent.right = v
+	}
+	v.parent = u.parent
+}
+
+func (t *rbTree[K, V]) leftRotate(x *treeNode[K, V]) {
+	y := x.right
+	x.right = y.left
+	if y.left != t.sentinel {
+		y.left.parent = x
+	}
+	y.parent = x.parent
+	if x.parent == t.sentinel {
+		t.root = y
+	} else if x == x.parent.left {
+		x.parent.left = y
+	} else {
+		x.parent.right = y
+	}
+	y.left = x
+	x.parent = y
+}
+
+func (t *rbTree[K, V]) rightRotate(x *treeNode[K, V]) {
+	y := x.left
+	x.left = y.right
+	if y.right != t.sentinel {
+		y.right.parent = x
+	}
+	y.parent = x.parent
+	if x.parent == t.sentinel {
+		t.root = y
+	} else if x == x.parent.right {
+		x.parent.right = y
+	} else {
+		x.parent.left = y
+	}
+	y.right = x
+	x.parent = y
+}
@@ -20,6 +20,7 @@ package collect
 
 import (
 	"fmt"
+	"iter"
 	"sync"
 )
 
@@ -135,6 +136,36 @@ func (a *safeList[E]) ForEach(f Consumer[E]) error {
 	return a.List.ForEach(f)
 }
 
+// All 返回的 iter.Seq 在整个 for range 循环期间持有读锁，循环结束后自动释放
+// 循环体内不能调用该 List 的写方法，否则会死锁
+func (a *safeList[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		a.RLock()
+		defer a.RUnlock()
+		a.List.All()(yield)
+	}
+}
+
+// Backward 返回的 iter.Seq 在整个 for range 循环期间持有读锁，循环结束后自动释放
+// 循环体内不能调用该 List 的写方法，否则会死锁
+func (a *safeList[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		a.RLock()
+		defer a.RUnlock()
+		a.List.Backward()(yield)
+	}
+}
+
+// All2 返回的 iter.Seq2 在整个 for range 循环期间持有读锁，循环结束后自动释放
+// 循环体内不能调用该 List 的写方法，否则会死锁
+func (a *safeList[E]) All2() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		a.RLock()
+		defer a.RUnlock()
+		a.List.All2()(yield)
+	}
+}
+
 func (a *safeList[E]) ReplaceAll(operator UnaryOperator[E]) {
 	a.Lock()
 	defer a.Unlock()
@@ -198,7 +229,11 @@ func (a *safeList[E]) ListIteratorAt(index int) ListIterator[E] {
 func (a *safeList[E]) SubList(fromIndex, toIndex int) List[E] {
 	a.RLock()
 	defer a.RUnlock()
-	return a.List.SubList(fromIndex, toIndex)
+	// 视图和原列表共享同一把锁
+	return &safeList[E]{
+		List:    a.List.SubList(fromIndex, toIndex),
+		RWMutex: a.RWMutex,
+	}
 }
 
 func (a *safeList[E]) String() string {
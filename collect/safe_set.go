@@ -0,0 +1,148 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+	"sync"
+)
+
+type safeSet[E comparable] struct {
+	Set[E]
+	*sync.RWMutex
+}
+
+// NewSafeSet 使用读写锁包装 set，返回并发安全的 Set
+// 通过 Iterator 获取的迭代器使用完之后必须调用 Close 进行关闭，释放锁
+func NewSafeSet[E comparable](set Set[E]) Set[E] {
+	return &safeSet[E]{
+		Set:     set,
+		RWMutex: &sync.RWMutex{},
+	}
+}
+
+func (a *safeSet[E]) Size() int {
+	a.RLock()
+	defer a.RUnlock()
+	return a.Set.Size()
+}
+
+func (a *safeSet[E]) IsEmpty() bool {
+	a.RLock()
+	defer a.RUnlock()
+	return a.Set.IsEmpty()
+}
+
+func (a *safeSet[E]) Contains(e E) bool {
+	a.RLock()
+	defer a.RUnlock()
+	return a.Set.Contains(e)
+}
+
+func (a *safeSet[E]) Iterator() Iterator[E] {
+	return newSafeSetIterator[E](a.Set, a.RWMutex)
+}
+
+func (a *safeSet[E]) ToArray() []E {
+	a.RLock()
+	defer a.RUnlock()
+	return a.Set.ToArray()
+}
+
+func (a *safeSet[E]) Add(e E) bool {
+	a.Lock()
+	defer a.Unlock()
+	return a.Set.Add(e)
+}
+
+func (a *safeSet[E]) Remove(e E) bool {
+	a.Lock()
+	defer a.Unlock()
+	return a.Set.Remove(e)
+}
+
+func (a *safeSet[E]) ContainsAll(c Collection[E]) bool {
+	a.RLock()
+	defer a.RUnlock()
+	return a.Set.ContainsAll(c)
+}
+
+func (a *safeSet[E]) AddAll(c Collection[E]) {
+	a.Lock()
+	defer a.Unlock()
+	a.Set.AddAll(c)
+}
+
+func (a *safeSet[E]) RemoveAll(c Collection[E]) int {
+	a.Lock()
+	defer a.Unlock()
+	return a.Set.RemoveAll(c)
+}
+
+func (a *safeSet[E]) RemoveIf(filter Predicate[E]) int {
+	a.Lock()
+	defer a.Unlock()
+	return a.Set.RemoveIf(filter)
+}
+
+func (a *safeSet[E]) RetainAll(c Collection[E]) int {
+	a.Lock()
+	defer a.Unlock()
+	return a.Set.RetainAll(c)
+}
+
+func (a *safeSet[E]) Clear() {
+	a.Lock()
+	defer a.Unlock()
+	a.Set.Clear()
+}
+
+func (a *safeSet[E]) Equals(c Collection[E]) bool {
+	a.RLock()
+	defer a.RUnlock()
+	return a.Set.Equals(c)
+}
+
+func (a *safeSet[E]) ForEach(f Consumer[E]) error {
+	a.RLock()
+	defer a.RUnlock()
+	return a.Set.ForEach(f)
+}
+
+// All 返回的 iter.Seq 在整个 for range 循环期间持有读锁，循环结束后自动释放
+// 循环体内不能调用该 Set 的写方法，否则会死锁
+func (a *safeSet[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		a.RLock()
+		defer a.RUnlock()
+		a.Set.All()(yield)
+	}
+}
+
+func (a *safeSet[E]) GetEqualComparator() constraints.EqualComparator[E] {
+	return a.Set.GetEqualComparator()
+}
+
+func (a *safeSet[E]) String() string {
+	a.RLock()
+	defer a.RUnlock()
+	return fmt.Sprintf("%+v", a.Set)
+}
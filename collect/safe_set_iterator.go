@@ -0,0 +1,92 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"sync"
+)
+
+// newSafeSetIterator 创建安全的迭代器，创建时获取 m 的读锁，直到调用 Close 才会释放
+// 一旦调用了迭代器的 Remove 方法来修改 Set 集合，m 锁将会升级为写锁
+// 安全的迭代器使用完之后必须主动调用 Close 进行关闭，释放锁。
+func newSafeSetIterator[E comparable](set Set[E], m *sync.RWMutex) Iterator[E] {
+	m.RLock()
+	return &safeSetIterator[E]{
+		Iterator: set.Iterator(),
+		m:        m,
+		RWMutex:  &sync.RWMutex{},
+	}
+}
+
+type safeSetIterator[E comparable] struct {
+	Iterator[E]
+	*sync.RWMutex
+	m       *sync.RWMutex
+	mup     bool
+	isClose bool
+}
+
+func (s *safeSetIterator[E]) HasNext() bool {
+	s.RWMutex.RLock()
+	defer s.RWMutex.RUnlock()
+	return s.Iterator.HasNext()
+}
+
+func (s *safeSetIterator[E]) Next() (e E, err error) {
+	// 获取下一个元素会更新内部指针，所有需要写锁
+	s.RWMutex.Lock()
+	defer s.RWMutex.Unlock()
+	return s.Iterator.Next()
+}
+
+func (s *safeSetIterator[E]) Remove() error {
+	s.RWMutex.Lock()
+	defer s.RWMutex.Unlock()
+	if s.isClose {
+		return ErrIteratorClose
+	}
+	// 第一次调用，升级锁
+	if !s.mup {
+		s.m.RUnlock()
+		s.m.Lock()
+		s.mup = true
+	}
+	return s.Iterator.Remove()
+}
+
+func (s *safeSetIterator[E]) ForEachRemaining(action Consumer[E]) error {
+	s.RWMutex.RLock()
+	defer s.RWMutex.RUnlock()
+	return s.Iterator.ForEachRemaining(action)
+}
+
+func (s *safeSetIterator[E]) Close() {
+	s.RWMutex.Lock()
+	defer s.RWMutex.Unlock()
+	if s.isClose {
+		return
+	}
+	s.Iterator.Close()
+	if s.mup {
+		s.m.Unlock()
+	} else {
+		s.m.RUnlock()
+	}
+	s.isClose = true
+}
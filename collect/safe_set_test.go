@@ -0,0 +1,113 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_safeSet_concurrent(t *testing.T) {
+	set := NewSafeSet(NewSet[int]())
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				set.Add(i)
+				set.Contains(i)
+				if i%10 == g {
+					set.Remove(i)
+				}
+			}
+			it := set.Iterator()
+			for it.HasNext() {
+				_, _ = it.Next()
+			}
+			it.Close()
+		}(g)
+	}
+	wg.Wait()
+	if set.Size() > 100 || set.Size() < 90 {
+		t.Errorf("Size() = %v, want between 90 and 100", set.Size())
+	}
+}
+
+func Test_safeSetIterator_remove(t *testing.T) {
+	set := NewSafeSet(SetOf(1, 2, 3, 4, 5))
+	it := set.Iterator()
+	added := make(chan struct{})
+	go func() {
+		// 迭代器关闭之前，写操作会被阻塞
+		set.Add(6)
+		close(added)
+	}()
+	for it.HasNext() {
+		v, _ := it.Next()
+		if v%2 == 0 {
+			if err := it.Remove(); err != nil {
+				t.Errorf("Remove() = %v, want nil", err)
+			}
+		}
+	}
+	select {
+	case <-added:
+		t.Fatal("Add() returned before iterator Close()")
+	case <-time.After(10 * time.Millisecond):
+	}
+	it.Close()
+	<-added
+	got := set.ToArray()
+	slices.Sort(got)
+	if !reflect.DeepEqual(got, []int{1, 3, 5, 6}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{1, 3, 5, 6})
+	}
+	if err := it.Remove(); err != ErrIteratorClose {
+		t.Errorf("Remove() = %v, want ErrIteratorClose", err)
+	}
+	// 重复关闭不会重复释放锁
+	it.Close()
+	if !set.Remove(6) {
+		t.Error("Remove(6) = false, want true")
+	}
+}
+
+func Test_safeSet_All(t *testing.T) {
+	set := NewSafeSet(NewLinkedHashSet[int]())
+	set.AddAll(wrapArrayList([]int{3, 1, 2}))
+	var got []int
+	for e := range set.All() {
+		got = append(got, e)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(got, []int{3, 1}) {
+		t.Errorf("All() = %v, want %v", got, []int{3, 1})
+	}
+	// 提前退出循环同样会释放读锁
+	set.Add(4)
+	if got := slices.Collect(set.All()); !reflect.DeepEqual(got, []int{3, 1, 2, 4}) {
+		t.Errorf("All() = %v, want %v", got, []int{3, 1, 2, 4})
+	}
+}
@@ -23,9 +23,30 @@ type Set[E comparable] interface {
 	Collection[E]
 }
 
-func NewSet[E comparable]() Set[E] {
+type SetConfig[E comparable] struct {
+	// JSONLess 不为 nil 时，序列化为 JSON 之前使用它对元素进行排序，使输出的顺序固定
+	// 默认 nil，按照 Set 的迭代顺序输出
+	JSONLess SortLess[E]
+}
+
+type SetOption[E comparable] func(config *SetConfig[E])
+
+// NewSet 创建一个基于 Go map 的 Set，迭代顺序不固定
+func NewSet[E comparable](options ...SetOption[E]) Set[E] {
+	var config SetConfig[E]
+	for _, option := range options {
+		option(&config)
+	}
 	return &hashSet[E]{
-		data: make(map[E]struct{}),
+		data:     make(map[E]struct{}),
+		jsonLess: config.JSONLess,
+	}
+}
+
+// WithJSONLess 设置序列化为 JSON 时元素的排序方法
+func WithJSONLess[E comparable](less SortLess[E]) SetOption[E] {
+	return func(config *SetConfig[E]) {
+		config.JSONLess = less
 	}
 }
 
@@ -21,11 +21,18 @@ package collect
 import "math"
 
 func NewSetIterator[E comparable](set Set[E]) Iterator[E] {
+	return newSnapshotIterator[E](set)
+}
+
+// newSnapshotIterator 创建基于集合快照的迭代器，迭代过程中集合的修改对迭代器不可见
+// Remove 方法会调用集合的 Remove 方法删除元素
+func newSnapshotIterator[E comparable](c Collection[E]) Iterator[E] {
+	values := c.ToArray()
 	return &setIterator[E]{
 		lastRet: -1,
-		size:    set.Size(),
-		values:  set.ToArray(),
-		set:     set,
+		size:    len(values),
+		values:  values,
+		set:     c,
 	}
 }
 
@@ -33,7 +40,7 @@ type setIterator[E comparable] struct {
 	cursor, lastRet, size int
 
 	isClose bool
-	set     Set[E]
+	set     Collection[E]
 	values  []E
 }
 
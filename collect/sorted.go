@@ -0,0 +1,97 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+// SortedSet 按照 SortLess 排序的 Set，迭代器按照升序返回元素
+// 两个元素 e1、e2 满足 !less(e1, e2) && !less(e2, e1) 时视为同一个元素
+type SortedSet[E comparable] interface {
+	Set[E]
+
+	// First 返回第一个（最小的）元素，如果集合为空第二个返回值为 false
+	First() (E, bool)
+
+	// Last 返回最后一个（最大的）元素，如果集合为空第二个返回值为 false
+	Last() (E, bool)
+
+	// Floor 返回小于等于 e 的最大元素，如果不存在第二个返回值为 false
+	Floor(e E) (E, bool)
+
+	// Ceiling 返回大于等于 e 的最小元素，如果不存在第二个返回值为 false
+	Ceiling(e E) (E, bool)
+
+	// Higher 返回大于 e 的最小元素，如果不存在第二个返回值为 false
+	Higher(e E) (E, bool)
+
+	// Lower 返回小于 e 的最大元素，如果不存在第二个返回值为 false
+	Lower(e E) (E, bool)
+
+	// HeadSet 返回小于 toElement 的元素组成的视图，inclusive 为 true 时包含等于 toElement 的元素
+	// 视图和原集合共享数据，对视图的修改会写回到原集合中，添加超出视图范围的元素会被忽略
+	HeadSet(toElement E, inclusive bool) SortedSet[E]
+
+	// TailSet 返回大于 fromElement 的元素组成的视图，inclusive 为 true 时包含等于 fromElement 的元素
+	// 视图和原集合共享数据，对视图的修改会写回到原集合中，添加超出视图范围的元素会被忽略
+	TailSet(fromElement E, inclusive bool) SortedSet[E]
+
+	// SubSet 返回从 fromElement 到 toElement 之间的元素组成的视图
+	// 视图和原集合共享数据，对视图的修改会写回到原集合中，添加超出视图范围的元素会被忽略
+	SubSet(fromElement E, fromInclusive bool, toElement E, toInclusive bool) SortedSet[E]
+
+	// DescendingIterator 返回按照降序迭代的迭代器
+	DescendingIterator() Iterator[E]
+}
+
+// SortedMap 按照键排序的 Map，ForEach 和各个视图都按照键的升序迭代
+// 两个键 k1、k2 满足 !less(k1, k2) && !less(k2, k1) 时视为同一个键
+type SortedMap[K comparable, V any] interface {
+	Map[K, V]
+
+	// FirstKey 返回第一个（最小的）键，如果 Map 为空第二个返回值为 false
+	FirstKey() (K, bool)
+
+	// LastKey 返回最后一个（最大的）键，如果 Map 为空第二个返回值为 false
+	LastKey() (K, bool)
+
+	// FloorKey 返回小于等于 k 的最大键，如果不存在第二个返回值为 false
+	FloorKey(k K) (K, bool)
+
+	// CeilingKey 返回大于等于 k 的最小键，如果不存在第二个返回值为 false
+	CeilingKey(k K) (K, bool)
+
+	// HigherKey 返回大于 k 的最小键，如果不存在第二个返回值为 false
+	HigherKey(k K) (K, bool)
+
+	// LowerKey 返回小于 k 的最大键，如果不存在第二个返回值为 false
+	LowerKey(k K) (K, bool)
+
+	// HeadMap 返回键小于 toKey 的部分组成的视图，inclusive 为 true 时包含键等于 toKey 的键值对
+	// 视图和原 Map 共享数据，对视图的修改会写回到原 Map 中，写入超出视图范围的键会被忽略
+	HeadMap(toKey K, inclusive bool) SortedMap[K, V]
+
+	// TailMap 返回键大于 fromKey 的部分组成的视图，inclusive 为 true 时包含键等于 fromKey 的键值对
+	// 视图和原 Map 共享数据，对视图的修改会写回到原 Map 中，写入超出视图范围的键会被忽略
+	TailMap(fromKey K, inclusive bool) SortedMap[K, V]
+
+	// SubMap 返回键从 fromKey 到 toKey 之间的部分组成的视图
+	// 视图和原 Map 共享数据，对视图的修改会写回到原 Map 中，写入超出视图范围的键会被忽略
+	SubMap(fromKey K, fromInclusive bool, toKey K, toInclusive bool) SortedMap[K, V]
+
+	// DescendingIterator 返回按照键的降序迭代键值对的迭代器
+	DescendingIterator() Iterator[Entry[K, V]]
+}
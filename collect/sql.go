@@ -0,0 +1,347 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SQLFormat 集合在数据库列中的编码格式
+type SQLFormat int
+
+const (
+	// SQLFormatJSON JSON 数组，例如 [1,2,3]、["a","b"]
+	SQLFormatJSON SQLFormat = iota
+	// SQLFormatPostgres Postgres 一维数组字面量，例如 {1,2,3}、{"a","b"}
+	SQLFormatPostgres
+)
+
+// SQLColumn 可以作为查询参数和扫描目标的数据库列
+type SQLColumn interface {
+	driver.Valuer
+	sql.Scanner
+}
+
+// List 和 Set 的实现都可以直接作为查询参数和扫描目标：
+// 作为查询参数时编码为 JSON 数组；扫描时根据内容自动识别 JSON 数组和 Postgres 数组，扫描到 NULL 时清空集合
+// 需要使用其它格式写入某一列时使用 SQLArray 包装
+
+// SQLArray 使用指定的格式包装集合 c，写入和读取数据库列
+func SQLArray[E any](c Collection[E], format SQLFormat) SQLColumn {
+	return &sqlArray[E]{c: c, format: format}
+}
+
+type sqlArray[E any] struct {
+	c      Collection[E]
+	format SQLFormat
+}
+
+func (s *sqlArray[E]) Value() (driver.Value, error) {
+	return sqlValue(s.c.ToArray(), s.format)
+}
+
+func (s *sqlArray[E]) Scan(src any) error {
+	return sqlScan(s.c, src)
+}
+
+func sqlValue[E any](values []E, format SQLFormat) (driver.Value, error) {
+	switch format {
+	case SQLFormatJSON:
+		data, err := marshalJSON(values)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	case SQLFormatPostgres:
+		return formatPostgresArray(values)
+	}
+	return nil, fmt.Errorf("unknown sql format %d", format)
+}
+
+func sqlScan[E any](c Collection[E], src any) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		c.Clear()
+		return nil
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return fmt.Errorf("cannot scan %T into collection", src)
+	}
+	trimmed := strings.TrimSpace(text)
+	if strings.HasPrefix(trimmed, "{") {
+		values, err := parsePostgresArray[E](trimmed)
+		if err != nil {
+			return err
+		}
+		resetCollection(c, values)
+		return nil
+	}
+	return unmarshalJSON(c, []byte(trimmed))
+}
+
+// jsonValue 使用集合自身的 JSON 编码作为列的值，Set 的排序配置同样生效
+func jsonValue(m json.Marshaler) (driver.Value, error) {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// formatPostgresArray 按照元素的底层类型编码：字符串加上引号，整数、浮点数、布尔类型原样输出，
+// 例如 type Status string 与 string 的编码相同；其它类型的元素编码为 JSON 后加上引号
+func formatPostgresArray[E any](values []E) (string, error) {
+	build := strings.Builder{}
+	build.WriteByte('{')
+	for i, e := range values {
+		if i > 0 {
+			build.WriteByte(',')
+		}
+		v := reflect.ValueOf(&e).Elem()
+		switch v.Kind() {
+		case reflect.String:
+			writePostgresQuoted(&build, v.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			build.WriteString(strconv.FormatInt(v.Int(), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			build.WriteString(strconv.FormatUint(v.Uint(), 10))
+		case reflect.Float32, reflect.Float64:
+			build.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()))
+		case reflect.Bool:
+			build.WriteString(strconv.FormatBool(v.Bool()))
+		default:
+			data, err := json.Marshal(e)
+			if err != nil {
+				return "", err
+			}
+			writePostgresQuoted(&build, string(data))
+		}
+	}
+	build.WriteByte('}')
+	return build.String(), nil
+}
+
+func writePostgresQuoted(build *strings.Builder, s string) {
+	build.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			build.WriteByte('\\')
+		}
+		build.WriteByte(s[i])
+	}
+	build.WriteByte('"')
+}
+
+var errPostgresArray = errors.New("invalid postgres array literal")
+
+// parsePostgresArray 解析一维的 Postgres 数组字面量，NULL 元素解析为零值
+func parsePostgresArray[E any](s string) ([]E, error) {
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, errPostgresArray
+	}
+	s = s[1 : len(s)-1]
+	var values []E
+	for i := 0; i < len(s); {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		var token string
+		var null bool
+		if i < len(s) && s[i] == '"' {
+			build := strings.Builder{}
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+					if i == len(s) {
+						return nil, errPostgresArray
+					}
+				}
+				build.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, errPostgresArray
+			}
+			i++
+			token = build.String()
+		} else {
+			start := i
+			for i < len(s) && s[i] != ',' {
+				if s[i] == '{' || s[i] == '"' {
+					return nil, fmt.Errorf("%w: multi-dimensional arrays are not supported", errPostgresArray)
+				}
+				i++
+			}
+			token = strings.TrimSpace(s[start:i])
+			if token == "" {
+				return nil, errPostgresArray
+			}
+			null = strings.EqualFold(token, "NULL")
+		}
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i < len(s) {
+			if s[i] != ',' {
+				return nil, errPostgresArray
+			}
+			i++
+			if i == len(s) {
+				return nil, errPostgresArray
+			}
+		}
+		var e E
+		if !null {
+			if err := parsePostgresElement(token, &e); err != nil {
+				return nil, err
+			}
+		}
+		values = append(values, e)
+	}
+	return values, nil
+}
+
+// parsePostgresElement 与 formatPostgresArray 对应，按照元素的底层类型解析
+func parsePostgresElement[E any](token string, e *E) error {
+	v := reflect.ValueOf(e).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(token)
+		return nil
+	case reflect.Bool:
+		switch strings.ToLower(token) {
+		case "t", "true":
+			v.SetBool(true)
+		case "f", "false":
+			v.SetBool(false)
+		default:
+			return fmt.Errorf("%w: invalid bool %q", errPostgresArray, token)
+		}
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(token, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %v", errPostgresArray, err)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(token, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %v", errPostgresArray, err)
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(token, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %v", errPostgresArray, err)
+		}
+		v.SetFloat(f)
+		return nil
+	}
+	// 其它类型按照 JSON 解析，失败时作为 JSON 字符串再解析一次
+	if err := json.Unmarshal([]byte(token), e); err != nil {
+		if err2 := json.Unmarshal([]byte(strconv.Quote(token)), e); err2 != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *arrayList[E]) Value() (driver.Value, error) {
+	return jsonValue(a)
+}
+
+func (a *arrayList[E]) Scan(src any) error {
+	return sqlScan[E](a, src)
+}
+
+func (l *linkedList[E]) Value() (driver.Value, error) {
+	return jsonValue(l)
+}
+
+func (l *linkedList[E]) Scan(src any) error {
+	return sqlScan[E](l, src)
+}
+
+func (s *subList[E]) Value() (driver.Value, error) {
+	return jsonValue(s)
+}
+
+func (s *subList[E]) Scan(src any) error {
+	if err := s.checkForComodification(); err != nil {
+		return err
+	}
+	return sqlScan[E](s, src)
+}
+
+func (a *safeList[E]) Value() (driver.Value, error) {
+	return jsonValue(a)
+}
+
+func (a *safeList[E]) Scan(src any) error {
+	a.Lock()
+	defer a.Unlock()
+	if err := checkView(a.List); err != nil {
+		return err
+	}
+	return sqlScan(a.List, src)
+}
+
+func (h *hashSet[E]) Value() (driver.Value, error) {
+	return jsonValue(h)
+}
+
+func (h *hashSet[E]) Scan(src any) error {
+	return sqlScan[E](h, src)
+}
+
+func (s *mapSet[E]) Value() (driver.Value, error) {
+	return jsonValue(s)
+}
+
+func (s *mapSet[E]) Scan(src any) error {
+	return sqlScan[E](s, src)
+}
+
+func (s *treeSet[E]) Scan(src any) error {
+	return sqlScan[E](s, src)
+}
+
+func (a *safeSet[E]) Value() (driver.Value, error) {
+	return jsonValue(a)
+}
+
+func (a *safeSet[E]) Scan(src any) error {
+	a.Lock()
+	defer a.Unlock()
+	return sqlScan(a.Set, src)
+}
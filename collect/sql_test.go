@@ -0,0 +1,212 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// fakeDriver 只有一列的内存表，INSERT 写入参数，SELECT 按照写入顺序返回所有行
+type fakeDriver struct {
+	mu   sync.Mutex
+	rows []driver.Value
+}
+
+type fakeConn struct{ d *fakeDriver }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+type fakeRows struct {
+	rows []driver.Value
+	i    int
+}
+
+var fakeDB = &fakeDriver{}
+
+func init() {
+	sql.Register("collect-fake", fakeDB)
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if s.query == "DELETE" {
+		s.d.rows = nil
+	} else {
+		s.d.rows = append(s.d.rows, args[0])
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &fakeRows{rows: slices.Clone(s.d.rows)}, nil
+}
+
+func (r *fakeRows) Columns() []string { return []string{"v"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i == len(r.rows) {
+		return io.EOF
+	}
+	dest[0] = r.rows[r.i]
+	r.i++
+	return nil
+}
+
+func openFakeDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("collect-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("DELETE"); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func Test_sql_list(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+	for _, c := range configList {
+		list := newArrayList[string](c, "a", `b"c`, `d\e`, "")
+		if _, err := db.Exec("INSERT", list); err != nil {
+			t.Fatalf("Exec() = %v, want nil", err)
+		}
+		if _, err := db.Exec("INSERT", SQLArray[string](list, SQLFormatPostgres)); err != nil {
+			t.Fatalf("Exec() = %v, want nil", err)
+		}
+	}
+	if fakeDB.rows[0] != `["a","b\"c","d\\e",""]` || fakeDB.rows[1] != `{"a","b\"c","d\\e",""}` {
+		t.Errorf("rows = %v", fakeDB.rows[:2])
+	}
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var n int
+	for ; rows.Next(); n++ {
+		got := NewList[string](configList[n/2])
+		if err := rows.Scan(got); err != nil {
+			t.Fatalf("Scan() = %v, want nil", err)
+		}
+		if want := []string{"a", `b"c`, `d\e`, ""}; !reflect.DeepEqual(got.ToArray(), want) {
+			t.Errorf("Scan() = %v, want %v", got, want)
+		}
+	}
+	if n != 2*len(configList) {
+		t.Errorf("rows = %v, want %v", n, 2*len(configList))
+	}
+}
+
+func Test_sql_set(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+	set := NewSet(WithJSONLess(SortLessOrdered[int](true)))
+	set.AddAll(wrapArrayList([]int{3, 1, 2}))
+	if _, err := db.Exec("INSERT", set); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT", SQLArray[int](NewOrderedTreeSet[int](false), SQLFormatPostgres)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT", nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fakeDB.rows, []driver.Value{"[1,2,3]", "{}", nil}) {
+		t.Errorf("rows = %v, want %v", fakeDB.rows, []any{"[1,2,3]", "{}", nil})
+	}
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	want := []Set[int]{SetOf(1, 2, 3), SetOf[int](), SetOf[int]()}
+	for i := 0; rows.Next(); i++ {
+		got := NewSafeSet(SetOf(9))
+		if err := rows.Scan(got); err != nil || !got.Equals(want[i]) {
+			t.Errorf("Scan() = %v, %v, want %v", err, got, want[i])
+		}
+	}
+}
+
+func Test_parsePostgresArray(t *testing.T) {
+	ints, err := parsePostgresArray[int](`{1, -2 ,NULL,4}`)
+	if err != nil || !reflect.DeepEqual(ints, []int{1, -2, 0, 4}) {
+		t.Errorf("parsePostgresArray() = %v, %v, want %v", ints, err, []int{1, -2, 0, 4})
+	}
+	bools, err := parsePostgresArray[bool](`{t,f,true}`)
+	if err != nil || !reflect.DeepEqual(bools, []bool{true, false, true}) {
+		t.Errorf("parsePostgresArray() = %v, %v, want %v", bools, err, []bool{true, false, true})
+	}
+	strs, err := parsePostgresArray[string](`{abc,"NULL","x,y"}`)
+	if err != nil || !reflect.DeepEqual(strs, []string{"abc", "NULL", "x,y"}) {
+		t.Errorf("parsePostgresArray() = %v, %v, want %v", strs, err, []string{"abc", "NULL", "x,y"})
+	}
+	for _, s := range []string{`{1,}`, `{{1,2},{3,4}}`, `{"a}`, `1,2`, `{a}`} {
+		if _, err := parsePostgresArray[int](s); err == nil {
+			t.Errorf("parsePostgresArray(%s) error = nil, want error", s)
+		}
+	}
+	type status string
+	type level uint8
+	statuses := []status{"active", `a"b`}
+	if s, err := formatPostgresArray(statuses); err != nil || s != `{"active","a\"b"}` {
+		t.Errorf("formatPostgresArray() = %v, %v, want %v", s, err, `{"active","a\"b"}`)
+	}
+	if got, err := parsePostgresArray[status](`{"active","a\"b"}`); err != nil || !reflect.DeepEqual(got, statuses) {
+		t.Errorf("parsePostgresArray() = %v, %v, want %v", got, err, statuses)
+	}
+	if s, _ := formatPostgresArray([]level{1, 255}); s != "{1,255}" {
+		t.Errorf("formatPostgresArray() = %v, want %v", s, "{1,255}")
+	}
+	if _, err := parsePostgresArray[level](`{256}`); err == nil {
+		t.Errorf("parsePostgresArray({256}) error = nil, want error")
+	}
+	structs := []TestStruct{{Id: 1, Data: []int{2}}}
+	s, _ := formatPostgresArray(structs)
+	got, err := parsePostgresArray[TestStruct](s)
+	if err != nil || !reflect.DeepEqual(got, structs) {
+		t.Errorf("parsePostgresArray(%s) = %v, %v, want %v", s, got, err, structs)
+	}
+}
@@ -0,0 +1,359 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+	"reflect"
+	"sort"
+)
+
+// Stream 惰性求值的元素流水线
+// 中间操作（Filter、Map、Limit 等）只会组合流水线，调用终止操作（Count、ToList 等）时才会真正迭代数据源，
+// Limit、FindFirst、AnyMatch 等操作满足条件后会立即停止迭代数据源
+// 改变元素类型的转换使用包函数 MapTo、FlatMapTo，要求元素可比较的 ToSet 同样为包函数
+// Stream 只能被终止操作消费一次，数据源为 Iterator 时再次消费不会得到任何元素
+type Stream[E any] interface {
+	// Filter 只保留 predicate 返回 true 的元素
+	Filter(predicate Predicate[E]) Stream[E]
+
+	// Map 将每个元素替换为 mapper 的返回值
+	Map(mapper UnaryOperator[E]) Stream[E]
+
+	// FlatMap 将每个元素替换为 mapper 返回的 Stream 中的所有元素
+	FlatMap(mapper func(e E) Stream[E]) Stream[E]
+
+	// Distinct 去除重复的元素，保留第一次出现的元素
+	// 元素的比较使用数据源集合的 GetEqualComparator；比较器与 == 语义一致时（默认的比较器），可比较的元素使用 map 查找，
+	// 其余情况（例如通过 WithEqualFunc 设置了比较函数）需要线性查找
+	Distinct() Stream[E]
+
+	// Sorted 使用 less 对元素进行稳定排序，排序需要先读取全部元素
+	Sorted(less SortLess[E]) Stream[E]
+
+	// Limit 最多保留前 n 个元素
+	Limit(n int) Stream[E]
+
+	// Skip 跳过前 n 个元素
+	Skip(n int) Stream[E]
+
+	// Peek 元素流过时调用 action，通常用于调试
+	Peek(action CallBack[E]) Stream[E]
+
+	// ForEach 终止操作，迭代所有元素，直到所有元素都被处理或返回错误
+	ForEach(f Consumer[E]) error
+
+	// Reduce 终止操作，以 identity 为初始值，依次使用 accumulator 合并所有元素
+	Reduce(identity E, accumulator func(e1, e2 E) E) E
+
+	// Count 终止操作，返回元素个数
+	Count() int
+
+	// AnyMatch 终止操作，存在元素满足 predicate 时返回 true
+	AnyMatch(predicate Predicate[E]) bool
+
+	// AllMatch 终止操作，所有元素都满足 predicate 时返回 true，没有元素时返回 true
+	AllMatch(predicate Predicate[E]) bool
+
+	// NoneMatch 终止操作，没有元素满足 predicate 时返回 true
+	NoneMatch(predicate Predicate[E]) bool
+
+	// FindFirst 终止操作，返回第一个元素，没有元素时第二个返回值为 false
+	FindFirst() (E, bool)
+
+	// ToList 终止操作，将所有元素收集到一个新的 List 中
+	ToList() List[E]
+
+	// All 返回迭代流水线中元素的 iter.Seq
+	All() iter.Seq[E]
+}
+
+// StreamOf 创建以集合 c 为数据源的 Stream，通过 c.All 迭代集合
+// 元素的比较使用 c.GetEqualComparator，c 没有比较器时使用 DefaultEqualFunc
+func StreamOf[E any](c Collection[E]) Stream[E] {
+	return &stream[E]{
+		seq:        c.All(),
+		comparator: orDefaultComparator(c.GetEqualComparator()),
+	}
+}
+
+// StreamOfIterator 创建以迭代器 it 为数据源的 Stream，迭代结束或者停止时会关闭迭代器
+// Next 返回错误时视为迭代结束
+func StreamOfIterator[E any](it Iterator[E]) Stream[E] {
+	return StreamOfSeq(func(yield func(E) bool) {
+		defer it.Close()
+		for it.HasNext() {
+			e, err := it.Next()
+			if err != nil || !yield(e) {
+				return
+			}
+		}
+	})
+}
+
+// StreamOfSeq 创建以 seq 为数据源的 Stream，元素的比较使用 DefaultEqualFunc
+func StreamOfSeq[E any](seq iter.Seq[E]) Stream[E] {
+	return &stream[E]{
+		seq:        seq,
+		comparator: defaultEqualComparator[E](),
+	}
+}
+
+// MapTo 将 s 中的每个元素转换为 mapper 的返回值
+func MapTo[E, R any](s Stream[E], mapper func(e E) R) Stream[R] {
+	return StreamOfSeq(func(yield func(R) bool) {
+		for e := range s.All() {
+			if !yield(mapper(e)) {
+				return
+			}
+		}
+	})
+}
+
+// FlatMapTo 将 s 中的每个元素替换为 mapper 返回的 Stream 中的所有元素
+func FlatMapTo[E, R any](s Stream[E], mapper func(e E) Stream[R]) Stream[R] {
+	return StreamOfSeq(func(yield func(R) bool) {
+		for e := range s.All() {
+			for r := range mapper(e).All() {
+				if !yield(r) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// ToSet 终止操作，将 s 中的所有元素收集到一个新的 Set 中
+func ToSet[E comparable](s Stream[E]) Set[E] {
+	set := NewSet[E]()
+	for e := range s.All() {
+		set.Add(e)
+	}
+	return set
+}
+
+type stream[E any] struct {
+	seq        iter.Seq[E]
+	comparator constraints.EqualComparator[E]
+}
+
+// then 使用新的数据源创建 Stream，保留元素比较器
+func (s *stream[E]) then(seq iter.Seq[E]) Stream[E] {
+	return &stream[E]{
+		seq:        seq,
+		comparator: s.comparator,
+	}
+}
+
+func (s *stream[E]) Filter(predicate Predicate[E]) Stream[E] {
+	return s.then(func(yield func(E) bool) {
+		for e := range s.seq {
+			if predicate(e) && !yield(e) {
+				return
+			}
+		}
+	})
+}
+
+func (s *stream[E]) Map(mapper UnaryOperator[E]) Stream[E] {
+	return s.then(func(yield func(E) bool) {
+		for e := range s.seq {
+			if !yield(mapper(e)) {
+				return
+			}
+		}
+	})
+}
+
+func (s *stream[E]) FlatMap(mapper func(e E) Stream[E]) Stream[E] {
+	return s.then(func(yield func(E) bool) {
+		for e := range s.seq {
+			for r := range mapper(e).All() {
+				if !yield(r) {
+					return
+				}
+			}
+		}
+	})
+}
+
+func (s *stream[E]) Distinct() Stream[E] {
+	_, equality := s.comparator.(equalityComparator)
+	return s.then(func(yield func(E) bool) {
+		// 比较器与 == 一致时可比较的元素使用 map 去重，其余的元素线性查找
+		seen := make(map[any]struct{})
+		var others []E
+		for e := range s.seq {
+			if v := reflect.ValueOf(any(e)); equality && v.IsValid() && v.Comparable() {
+				if _, ok := seen[any(e)]; ok {
+					continue
+				}
+				seen[any(e)] = struct{}{}
+			} else {
+				if s.containsOther(others, e) {
+					continue
+				}
+				others = append(others, e)
+			}
+			if !yield(e) {
+				return
+			}
+		}
+	})
+}
+
+func (s *stream[E]) containsOther(others []E, e E) bool {
+	for _, o := range others {
+		if s.comparator.Equal(o, e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *stream[E]) Sorted(less SortLess[E]) Stream[E] {
+	return s.then(func(yield func(E) bool) {
+		var data []E
+		for e := range s.seq {
+			data = append(data, e)
+		}
+		sort.SliceStable(data, func(i, j int) bool {
+			return less(data[i], data[j])
+		})
+		for _, e := range data {
+			if !yield(e) {
+				return
+			}
+		}
+	})
+}
+
+func (s *stream[E]) Limit(n int) Stream[E] {
+	return s.then(func(yield func(E) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for e := range s.seq {
+			i++
+			if !yield(e) || i >= n {
+				return
+			}
+		}
+	})
+}
+
+func (s *stream[E]) Skip(n int) Stream[E] {
+	return s.then(func(yield func(E) bool) {
+		i := 0
+		for e := range s.seq {
+			if i < n {
+				i++
+				continue
+			}
+			if !yield(e) {
+				return
+			}
+		}
+	})
+}
+
+func (s *stream[E]) Peek(action CallBack[E]) Stream[E] {
+	return s.then(func(yield func(E) bool) {
+		for e := range s.seq {
+			action(e)
+			if !yield(e) {
+				return
+			}
+		}
+	})
+}
+
+func (s *stream[E]) ForEach(f Consumer[E]) error {
+	for e := range s.seq {
+		if err := f(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *stream[E]) Reduce(identity E, accumulator func(e1, e2 E) E) E {
+	res := identity
+	for e := range s.seq {
+		res = accumulator(res, e)
+	}
+	return res
+}
+
+func (s *stream[E]) Count() int {
+	var cnt int
+	for range s.seq {
+		cnt++
+	}
+	return cnt
+}
+
+func (s *stream[E]) AnyMatch(predicate Predicate[E]) bool {
+	for e := range s.seq {
+		if predicate(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *stream[E]) AllMatch(predicate Predicate[E]) bool {
+	for e := range s.seq {
+		if !predicate(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *stream[E]) NoneMatch(predicate Predicate[E]) bool {
+	return !s.AnyMatch(predicate)
+}
+
+func (s *stream[E]) FindFirst() (E, bool) {
+	for e := range s.seq {
+		return e, true
+	}
+	var zero E
+	return zero, false
+}
+
+func (s *stream[E]) ToList() List[E] {
+	var data []E
+	for e := range s.seq {
+		data = append(data, e)
+	}
+	return &arrayList[E]{
+		elementData: data,
+		capacity:    len(data),
+		size:        len(data),
+		comparator:  s.comparator,
+	}
+}
+
+func (s *stream[E]) All() iter.Seq[E] {
+	return s.seq
+}
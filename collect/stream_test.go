@@ -0,0 +1,148 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func Test_stream(t *testing.T) {
+	list := newArrayList[int](DefaultListConfig, 5, 3, 8, 3, 1, 9, 8, 2)
+	var peeked []int
+	got := StreamOf[int](list).
+		Peek(func(e int) { peeked = append(peeked, e) }).
+		Filter(func(e int) bool { return e > 1 }).
+		Distinct().
+		Map(func(e int) int { return e * 10 }).
+		Sorted(SortLessOrdered[int](true)).
+		Skip(1).
+		Limit(3).
+		ToList()
+	if want := []int{30, 50, 80}; !reflect.DeepEqual(got.ToArray(), want) {
+		t.Errorf("ToList() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(peeked, list.ToArray()) {
+		t.Errorf("Peek() = %v, want %v", peeked, list.ToArray())
+	}
+	if n := StreamOf[int](list).Distinct().Count(); n != 6 {
+		t.Errorf("Count() = %v, want 6", n)
+	}
+	if sum := StreamOf[int](list).Reduce(0, func(e1, e2 int) int { return e1 + e2 }); sum != 39 {
+		t.Errorf("Reduce() = %v, want 39", sum)
+	}
+}
+
+func Test_stream_shortCircuit(t *testing.T) {
+	var pulled int
+	s := StreamOfSeq[int](func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	})
+	if !s.AnyMatch(func(e int) bool { return e == 3 }) || pulled != 4 {
+		t.Errorf("AnyMatch() pulled %d elements, want 4", pulled)
+	}
+	pulled = 0
+	if e, ok := s.Skip(2).FindFirst(); !ok || e != 2 || pulled != 3 {
+		t.Errorf("FindFirst() = %v, %v, pulled %d, want 2, true, 3", e, ok, pulled)
+	}
+	if s.AllMatch(func(e int) bool { return e < 5 }) {
+		t.Error("AllMatch() = true, want false")
+	}
+	if !s.Limit(5).NoneMatch(func(e int) bool { return e >= 5 }) {
+		t.Error("NoneMatch() = false, want true")
+	}
+	if n := s.Limit(0).Count(); n != 0 {
+		t.Errorf("Limit(0).Count() = %v, want 0", n)
+	}
+}
+
+func Test_stream_iterator(t *testing.T) {
+	set := NewSafeSet(SetOf(1, 2, 3, 4))
+	s := StreamOfIterator(set.Iterator())
+	if e, ok := s.Filter(func(e int) bool { return e%2 == 0 }).FindFirst(); !ok || e%2 != 0 {
+		t.Errorf("FindFirst() = %v, %v, want even, true", e, ok)
+	}
+	// 迭代器已关闭，写操作不会被阻塞
+	set.Add(5)
+	got := ToSet(FlatMapTo(StreamOf[int](set), func(e int) Stream[string] {
+		return StreamOfSeq(slices.Values([]string{strconv.Itoa(e), strconv.Itoa(-e)}))
+	}).Filter(func(e string) bool { return e[0] != '-' }))
+	if !got.Equals(SetOf("1", "2", "3", "4", "5")) {
+		t.Errorf("ToSet() = %v, want %v", got, []string{"1", "2", "3", "4", "5"})
+	}
+	names := MapTo(StreamOf[int](wrapArrayList([]int{1, 2})), strconv.Itoa).
+		FlatMap(func(e string) Stream[string] {
+			return StreamOfSeq(slices.Values([]string{e, e + e}))
+		}).ToList()
+	if want := []string{"1", "11", "2", "22"}; !reflect.DeepEqual(names.ToArray(), want) {
+		t.Errorf("FlatMap() = %v, want %v", names, want)
+	}
+	errStop := errors.New("stop")
+	var cnt int
+	err := StreamOf[string](names).ForEach(func(e string) error {
+		cnt++
+		return errStop
+	})
+	if !errors.Is(err, errStop) || cnt != 1 {
+		t.Errorf("ForEach() = %v, %d, want %v, 1", err, cnt, errStop)
+	}
+}
+
+func Test_stream_distinctIncomparable(t *testing.T) {
+	list := newArrayList[TestStruct](DefaultListConfig,
+		TestStruct{Id: 1, Data: []int{1}},
+		TestStruct{Id: 1, Data: []int{1}},
+		TestStruct{Id: 2, Data: []int{2}},
+	)
+	if n := StreamOf[TestStruct](list).Distinct().Count(); n != 2 {
+		t.Errorf("Distinct().Count() = %v, want 2", n)
+	}
+}
+
+func Test_stream_setSource(t *testing.T) {
+	// Set 的 GetEqualComparator 返回 nil，Stream 使用默认的比较器
+	list := StreamOf[int](SetOf(1, 2)).ToList()
+	if !list.Contains(1) || list.Contains(3) {
+		t.Errorf("ToList() = %v, Contains(1) = %v", list, list.Contains(1))
+	}
+	if got := StreamOf[int](SetOf(1, 2, 3)).Distinct().Count(); got != 3 {
+		t.Errorf("Distinct().Count() = %d, want 3", got)
+	}
+}
+
+func Test_stream_distinctComparator(t *testing.T) {
+	// 使用 WithEqualFunc 设置的比较器去重：按照绝对值比较
+	list := NewList[int](DefaultListConfig, WithEqualFunc(func(v1, v2 int) bool {
+		return v1 == v2 || v1 == -v2
+	}))
+	for _, v := range []int{1, -1, 2, 3, -2} {
+		list.Add(v)
+	}
+	if got := StreamOf(list).Distinct().ToList().ToArray(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Distinct() = %v, want [1 2 3]", got)
+	}
+}
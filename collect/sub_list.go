@@ -0,0 +1,466 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"container/list"
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"iter"
+	"sort"
+)
+
+// CopyOf 复制 list 中的元素，返回与 list 底层实现相同的新 List，修改新 List 不会影响 list
+// 需要复制部分元素时可以使用 CopyOf(list.SubList(fromIndex, toIndex))，返回与原列表底层实现相同的 List
+func CopyOf[E any](l List[E]) List[E] {
+	switch v := l.(type) {
+	case *safeList[E]:
+		v.RLock()
+		defer v.RUnlock()
+		return NewSafeList[E](CopyOf[E](v.List))
+	case *linkedList[E]:
+		res := NewLinkedList[E](v.comparator)
+		res.AddAll(v)
+		return res
+	case *subList[E]:
+		if root, ok := v.root.(*linkedList[E]); ok {
+			res := NewLinkedList[E](root.comparator)
+			res.AddAll(v)
+			return res
+		}
+	}
+	data := l.ToArray()
+	return &arrayList[E]{
+		elementData: data,
+		capacity:    len(data),
+		size:        len(data),
+		comparator:  l.GetEqualComparator(),
+	}
+}
+
+// root 的类型为 *arrayList 或 *linkedList
+func newSubList[E any](root List[E], parent *subList[E], fromIndex, toIndex, size int) *subList[E] {
+	if fromIndex < 0 || fromIndex > toIndex || toIndex > size {
+		panic(fmt.Sprintf("sub list range [%d:%d] with length %d", fromIndex, toIndex, size))
+	}
+	offset := fromIndex
+	if parent != nil {
+		offset += parent.offset
+	}
+	return &subList[E]{
+		root:     root,
+		parent:   parent,
+		offset:   offset,
+		size:     toIndex - fromIndex,
+		modCount: root.(modCounter).structuralModCount(),
+	}
+}
+
+// subList List 的视图，所有读写操作都通过 offset 转换索引后作用到 root 上
+// 通过视图以外的操作对 root 进行结构性修改后视图失效，Get、Set、AddAt、RemoveAt、ForEach、迭代器的 Next 以及序列化等返回 error 的方法
+// 返回 ErrConcurrentModification；Size、IsEmpty 返回失效前的大小，String 返回错误信息；
+// Contains、Add、RemoveIfN、ToArray 等其它不返回 error 的方法以及 All 等迭代方法会 panic
+type subList[E any] struct {
+	root List[E]
+	// parent 嵌套创建视图时的上一级视图，视图的结构性修改需要同步更新所有上级视图的大小
+	parent *subList[E]
+	// offset 视图第一个元素在 root 中的索引
+	offset, size int
+	// modCount 视图期望的 root 修改次数
+	modCount int
+}
+
+func (s *subList[E]) checkForComodification() error {
+	if s.root.(modCounter).structuralModCount() != s.modCount {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
+func (s *subList[E]) mustCheck() {
+	if err := s.checkForComodification(); err != nil {
+		panic(err)
+	}
+}
+
+// updateSize 通过视图进行结构性修改之后，更新当前视图和所有上级视图的大小和修改次数
+func (s *subList[E]) updateSize(delta int) {
+	modCount := s.root.(modCounter).structuralModCount()
+	for v := s; v != nil; v = v.parent {
+		v.size += delta
+		v.modCount = modCount
+	}
+}
+
+func (s *subList[E]) rangeCheck(index int) error {
+	if index < 0 || index >= s.size {
+		return fmt.Errorf("index out of range [%d] with length %d", index, s.size)
+	}
+	return nil
+}
+
+// each 按照顺序迭代视图中的元素，f 返回 false 时停止迭代
+func (s *subList[E]) each(f func(e E) bool) {
+	s.mustCheck()
+	if s.size == 0 {
+		return
+	}
+	switch root := s.root.(type) {
+	case *arrayList[E]:
+		for _, e := range root.elementData[s.offset : s.offset+s.size] {
+			if !f(e) {
+				return
+			}
+		}
+	case *linkedList[E]:
+		for cur, i := s.element(root), 0; i < s.size; cur, i = cur.Next(), i+1 {
+			if !f(cur.Value.(E)) {
+				return
+			}
+		}
+	}
+}
+
+// element 返回视图第一个元素在链表中的节点
+func (s *subList[E]) element(root *linkedList[E]) *list.Element {
+	e, _ := root.getElement(s.offset)
+	return e
+}
+
+// setAll 按照顺序使用 values 替换视图中的元素
+func (s *subList[E]) setAll(values []E) {
+	switch root := s.root.(type) {
+	case *arrayList[E]:
+		copy(root.elementData[s.offset:s.offset+s.size], values)
+	case *linkedList[E]:
+		for cur, i := s.element(root), 0; i < len(values); cur, i = cur.Next(), i+1 {
+			cur.Value = values[i]
+		}
+	}
+}
+
+func (s *subList[E]) Size() int {
+	return s.size
+}
+
+func (s *subList[E]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+func (s *subList[E]) Contains(e E) bool {
+	return s.IndexOf(e) >= 0
+}
+
+func (s *subList[E]) Iterator() Iterator[E] {
+	return s.ListIterator()
+}
+
+func (s *subList[E]) ToArray() []E {
+	var res []E
+	s.each(func(e E) bool {
+		res = append(res, e)
+		return true
+	})
+	return res
+}
+
+func (s *subList[E]) Add(e E) bool {
+	s.mustCheck()
+	if s.offset+s.size == s.root.Size() {
+		s.root.Add(e)
+	} else if err := s.root.AddAt(s.offset+s.size, e); err != nil {
+		panic(err)
+	}
+	s.updateSize(1)
+	return true
+}
+
+func (s *subList[E]) Remove(e E) bool {
+	return s.RemoveN(e, 1) == 1
+}
+
+func (s *subList[E]) ContainsAll(c Collection[E]) bool {
+	itr := c.Iterator()
+	for itr.HasNext() {
+		if e, err := itr.Next(); err != nil || !s.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *subList[E]) AddAll(c Collection[E]) {
+	for _, e := range c.ToArray() {
+		s.Add(e)
+	}
+}
+
+func (s *subList[E]) RemoveAll(c Collection[E]) int {
+	return s.RemoveIfN(func(e E) bool {
+		return c.Contains(e)
+	}, -1)
+}
+
+func (s *subList[E]) RemoveIf(filter Predicate[E]) int {
+	return s.RemoveIfN(filter, -1)
+}
+
+func (s *subList[E]) RetainAll(c Collection[E]) int {
+	return s.RemoveIfN(func(e E) bool {
+		return !c.Contains(e)
+	}, -1)
+}
+
+func (s *subList[E]) Clear() {
+	s.RemoveIfN(func(e E) bool {
+		return true
+	}, -1)
+}
+
+func (s *subList[E]) Equals(c Collection[E]) bool {
+	return equals[E](s, c)
+}
+
+func (s *subList[E]) ForEach(f Consumer[E]) error {
+	if err := s.checkForComodification(); err != nil {
+		return err
+	}
+	var err error
+	s.each(func(e E) bool {
+		err = f(e)
+		return err == nil
+	})
+	return err
+}
+
+func (s *subList[E]) All() iter.Seq[E] {
+	return s.each
+}
+
+func (s *subList[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		values := s.ToArray()
+		for i := len(values) - 1; i >= 0; i-- {
+			if !yield(values[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (s *subList[E]) All2() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		i := 0
+		s.each(func(e E) bool {
+			ok := yield(i, e)
+			i++
+			return ok
+		})
+	}
+}
+
+func (s *subList[E]) GetEqualComparator() constraints.EqualComparator[E] {
+	return s.root.GetEqualComparator()
+}
+
+func (s *subList[E]) ReplaceAll(operator UnaryOperator[E]) {
+	if operator == nil {
+		return
+	}
+	values := s.ToArray()
+	for i, e := range values {
+		values[i] = operator(e)
+	}
+	s.setAll(values)
+}
+
+func (s *subList[E]) Sort(less SortLess[E]) {
+	values := s.ToArray()
+	sort.Slice(values, func(i, j int) bool {
+		return less(values[i], values[j])
+	})
+	s.setAll(values)
+}
+
+func (s *subList[E]) Get(index int) (E, error) {
+	if err := s.checkForComodification(); err != nil {
+		var zero E
+		return zero, err
+	}
+	if err := s.rangeCheck(index); err != nil {
+		var zero E
+		return zero, err
+	}
+	return s.root.Get(s.offset + index)
+}
+
+func (s *subList[E]) Set(index int, e E) (E, error) {
+	if err := s.checkForComodification(); err != nil {
+		var zero E
+		return zero, err
+	}
+	if err := s.rangeCheck(index); err != nil {
+		var zero E
+		return zero, err
+	}
+	return s.root.Set(s.offset+index, e)
+}
+
+func (s *subList[E]) AddAt(index int, e E) error {
+	if err := s.checkForComodification(); err != nil {
+		return err
+	}
+	if err := s.rangeCheck(index); err != nil {
+		return err
+	}
+	if err := s.root.AddAt(s.offset+index, e); err != nil {
+		return err
+	}
+	s.updateSize(1)
+	return nil
+}
+
+func (s *subList[E]) RemoveAt(index int) (E, error) {
+	if err := s.checkForComodification(); err != nil {
+		var zero E
+		return zero, err
+	}
+	if err := s.rangeCheck(index); err != nil {
+		var zero E
+		return zero, err
+	}
+	old, err := s.root.RemoveAt(s.offset + index)
+	if err != nil {
+		return old, err
+	}
+	s.updateSize(-1)
+	return old, nil
+}
+
+func (s *subList[E]) IndexOf(e E) int {
+	comparator := s.GetEqualComparator()
+	index, i := -1, 0
+	s.each(func(o E) bool {
+		if comparator.Equal(e, o) {
+			index = i
+			return false
+		}
+		i++
+		return true
+	})
+	return index
+}
+
+func (s *subList[E]) LastIndexOf(e E) int {
+	comparator := s.GetEqualComparator()
+	index, i := -1, 0
+	s.each(func(o E) bool {
+		if comparator.Equal(e, o) {
+			index = i
+		}
+		i++
+		return true
+	})
+	return index
+}
+
+func (s *subList[E]) ListIterator() ListIterator[E] {
+	return s.ListIteratorAt(0)
+}
+
+func (s *subList[E]) ListIteratorAt(index int) ListIterator[E] {
+	return newListIterator[E](s, index)
+}
+
+func (s *subList[E]) SubList(fromIndex, toIndex int) List[E] {
+	s.mustCheck()
+	return newSubList(s.root, s, fromIndex, toIndex, s.size)
+}
+
+func (s *subList[E]) RemoveN(e E, n int) int {
+	comparator := s.GetEqualComparator()
+	return s.RemoveIfN(func(o E) bool {
+		return comparator.Equal(e, o)
+	}, n)
+}
+
+func (s *subList[E]) RemoveIfN(filter Predicate[E], n int) int {
+	s.mustCheck()
+	if n == 0 || s.size == 0 {
+		return 0
+	} else if n == -1 {
+		n = s.size
+	}
+	if root, ok := s.root.(*arrayList[E]); ok {
+		cnt := root.removeIfRange(s.offset, s.offset+s.size, filter, n)
+		if cnt > 0 {
+			s.updateSize(-cnt)
+		}
+		return cnt
+	}
+	// 使用 root 的迭代器删除元素，避免链表按照索引查找
+	itr := s.root.ListIteratorAt(s.offset)
+	var cnt int
+	for i := 0; i < s.size && cnt < n; i++ {
+		e, err := itr.Next()
+		if err != nil {
+			panic(err)
+		}
+		if filter(e) {
+			if err = itr.Remove(); err != nil {
+				panic(err)
+			}
+			cnt++
+		}
+	}
+	itr.Close()
+	if cnt > 0 {
+		s.updateSize(-cnt)
+	}
+	return cnt
+}
+
+// structuralModCount 返回 root 的修改次数，视图的迭代器可以发现视图以外的操作对 root 的修改
+func (s *subList[E]) structuralModCount() int {
+	return s.root.(modCounter).structuralModCount()
+}
+
+// checkView l 为失效的视图时返回 ErrConcurrentModification，用于 safeList 等包装了视图的 List
+func checkView[E any](l List[E]) error {
+	if s, ok := l.(*subList[E]); ok {
+		return s.checkForComodification()
+	}
+	return nil
+}
+
+// snapshot 返回视图中元素的副本，视图失效时返回 ErrConcurrentModification
+func (s *subList[E]) snapshot() ([]E, error) {
+	if err := s.checkForComodification(); err != nil {
+		return nil, err
+	}
+	return s.ToArray(), nil
+}
+
+func (s *subList[E]) String() string {
+	values, err := s.snapshot()
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return fmt.Sprintf("%v", values)
+}
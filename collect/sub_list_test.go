@@ -0,0 +1,242 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func Test_subList_writeThrough(t *testing.T) {
+	for _, c := range configList {
+		list := newArrayList[int](c, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+		sub := list.SubList(2, 6)
+		if _, err := sub.Set(0, 30); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		sub.Add(60)
+		if err := sub.AddAt(1, 35); err != nil {
+			t.Fatalf("AddAt() error = %v", err)
+		}
+		if got := list.ToArray(); !reflect.DeepEqual(got, []int{1, 2, 30, 35, 4, 5, 6, 60, 7, 8, 9, 10}) {
+			t.Errorf("list = %v", got)
+		}
+		sub.Sort(func(e1, e2 int) bool { return e1 > e2 })
+		if got := sub.ToArray(); !reflect.DeepEqual(got, []int{60, 35, 30, 6, 5, 4}) {
+			t.Errorf("sub = %v", got)
+		}
+		if old, err := sub.RemoveAt(0); err != nil || old != 60 {
+			t.Errorf("RemoveAt() = %v, %v", old, err)
+		}
+		if cnt := sub.RemoveIf(func(e int) bool { return e%2 == 0 }); cnt != 3 {
+			t.Errorf("RemoveIf() = %v, want 3", cnt)
+		}
+		if got := list.ToArray(); !reflect.DeepEqual(got, []int{1, 2, 35, 5, 7, 8, 9, 10}) {
+			t.Errorf("list = %v", got)
+		}
+		sub.Clear()
+		if !sub.IsEmpty() || list.Size() != 6 {
+			t.Errorf("Clear() sub = %v, list = %v", sub, list)
+		}
+		if _, err := sub.Get(0); err == nil {
+			t.Errorf("Get() on empty sub list want error")
+		}
+	}
+}
+
+func Test_subList_invalidate(t *testing.T) {
+	for _, c := range configList {
+		list := newArrayList[int](c, 1, 2, 3, 4, 5)
+		sub := list.SubList(1, 3)
+		// 修改元素的值不是结构性修改，视图仍然有效
+		list.Set(1, 20)
+		if v, err := sub.Get(0); err != nil || v != 20 {
+			t.Errorf("Get() = %v, %v", v, err)
+		}
+		list.Add(6)
+		if _, err := sub.Get(0); !errors.Is(err, ErrConcurrentModification) {
+			t.Errorf("Get() error = %v, want %v", err, ErrConcurrentModification)
+		}
+		if err := sub.ForEach(func(int) error { return nil }); !errors.Is(err, ErrConcurrentModification) {
+			t.Errorf("ForEach() error = %v, want %v", err, ErrConcurrentModification)
+		}
+		// Size 返回失效前的大小，String 不会 panic
+		if n := sub.Size(); n != 2 || fmt.Sprint(sub) != "<concurrent modification>" {
+			t.Errorf("Size() = %v, String() = %v", n, fmt.Sprint(sub))
+		}
+		itr := sub.Iterator()
+		if !itr.HasNext() {
+			t.Fatal("HasNext() = false, want true")
+		}
+		if _, err := itr.Next(); !errors.Is(err, ErrConcurrentModification) {
+			t.Errorf("Next() error = %v, want %v", err, ErrConcurrentModification)
+		}
+		itr.Close()
+		if _, err := json.Marshal(sub); !errors.Is(err, ErrConcurrentModification) {
+			t.Errorf("json.Marshal() error = %v, want %v", err, ErrConcurrentModification)
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != ErrConcurrentModification {
+					t.Errorf("Contains() panic = %v, want %v", r, ErrConcurrentModification)
+				}
+			}()
+			sub.Contains(1)
+		}()
+		// 视图创建迭代器之后 root 被修改，线程安全的迭代器在关闭之前持有读锁，直接修改未包装的 List
+		sub = list.SubList(0, 2)
+		if safe, ok := list.(*safeList[int]); ok {
+			sub = safe.List.SubList(0, 2)
+			list = safe.List
+		}
+		itr = sub.Iterator()
+		itr.Next()
+		list.RemoveAt(5)
+		for itr.HasNext() {
+			if _, err := itr.Next(); !errors.Is(err, ErrConcurrentModification) {
+				t.Errorf("Next() error = %v, want %v", err, ErrConcurrentModification)
+			}
+			break
+		}
+	}
+}
+
+func Test_subList_nested(t *testing.T) {
+	for _, c := range configList {
+		list := newArrayList[int](c, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+		sub := list.SubList(2, 8)
+		nested := sub.SubList(1, 3)
+		if got := nested.ToArray(); !reflect.DeepEqual(got, []int{3, 4}) {
+			t.Errorf("nested = %v", got)
+		}
+		nested.Add(100)
+		if sub.Size() != 7 || list.Size() != 11 {
+			t.Errorf("Size() sub = %d, list = %d", sub.Size(), list.Size())
+		}
+		if got := sub.ToArray(); !reflect.DeepEqual(got, []int{2, 3, 4, 100, 5, 6, 7}) {
+			t.Errorf("sub = %v", got)
+		}
+		itr := nested.ListIterator()
+		for itr.HasNext() {
+			itr.Next()
+			itr.Remove()
+		}
+		itr.Close()
+		if got := list.ToArray(); !reflect.DeepEqual(got, []int{0, 1, 2, 5, 6, 7, 8, 9}) {
+			t.Errorf("list = %v", got)
+		}
+		// 通过嵌套视图修改后，上级视图仍然有效，同级的其它视图失效
+		other := sub.SubList(0, 1)
+		sub.Add(50)
+		if got := sub.ToArray(); !reflect.DeepEqual(got, []int{2, 5, 6, 7, 50}) {
+			t.Errorf("sub = %v", got)
+		}
+		if _, err := other.Get(0); !errors.Is(err, ErrConcurrentModification) {
+			t.Errorf("Get() error = %v, want %v", err, ErrConcurrentModification)
+		}
+	}
+}
+
+func Test_CopyOf(t *testing.T) {
+	for _, c := range configList {
+		list := newArrayList[int](c, 1, 2, 3, 4, 5)
+		cp := CopyOf(list.SubList(1, 4))
+		cp.Add(10)
+		list.Add(6)
+		if got := cp.ToArray(); !reflect.DeepEqual(got, []int{2, 3, 4, 10}) {
+			t.Errorf("CopyOf() = %v", got)
+		}
+		if got := list.ToArray(); !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5, 6}) {
+			t.Errorf("list = %v", got)
+		}
+		if reflect.TypeOf(CopyOf(list)) != reflect.TypeOf(list) {
+			t.Errorf("CopyOf() type = %T, want %T", CopyOf(list), list)
+		}
+		if cp := CopyOf(list.SubList(1, 3)); reflect.TypeOf(cp) != reflect.TypeOf(list) {
+			t.Errorf("CopyOf() type = %T, want %T", cp, list)
+		}
+	}
+}
+
+func Test_subList_RemoveIfN(t *testing.T) {
+	for _, c := range configList {
+		list := newArrayList[int](c, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+		sub := list.SubList(2, 8)
+		if cnt := sub.RemoveIfN(func(e int) bool { return e%2 == 1 }, 2); cnt != 2 {
+			t.Errorf("RemoveIfN() = %v, want 2", cnt)
+		}
+		if got := list.ToArray(); !reflect.DeepEqual(got, []int{0, 1, 2, 4, 6, 7, 8, 9}) {
+			t.Errorf("list = %v", got)
+		}
+		if got := sub.ToArray(); !reflect.DeepEqual(got, []int{2, 4, 6, 7}) {
+			t.Errorf("sub = %v", got)
+		}
+		sub.Clear()
+		if got := list.ToArray(); !reflect.DeepEqual(got, []int{0, 1, 8, 9}) || sub.Size() != 0 {
+			t.Errorf("Clear() sub = %v, list = %v", sub, got)
+		}
+	}
+	// 删除之后 arrayList 末尾空出的位置需要清零
+	list := NewArrayList[*int](0, comparableEqual[*int]())
+	for i := 0; i < 5; i++ {
+		list.Add(new(int))
+	}
+	list.SubList(1, 4).Clear()
+	if a := list.(*arrayList[*int]); a.size != 2 || a.elementData[2] != nil || a.elementData[4] != nil {
+		t.Errorf("elementData = %v", a.elementData)
+	}
+}
+
+func Test_subList_marshal(t *testing.T) {
+	for _, c := range configList {
+		list := newArrayList[int](c, 1, 2, 3, 4, 5)
+		sub := list.SubList(1, 3)
+		data, err := json.Marshal(struct{ L List[int] }{sub})
+		if err != nil || string(data) != `{"L":[2,3]}` {
+			t.Errorf("json.Marshal() = %s, %v, want %s", data, err, `{"L":[2,3]}`)
+		}
+		if err := json.Unmarshal([]byte("[7,8,9]"), sub); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if got := list.ToArray(); !reflect.DeepEqual(got, []int{1, 7, 8, 9, 4, 5}) {
+			t.Errorf("list = %v", got)
+		}
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(sub); err != nil {
+			t.Fatalf("gob Encode() error = %v", err)
+		}
+		got := NewList[int](c)
+		if err := gob.NewDecoder(&buf).Decode(got); err != nil || !reflect.DeepEqual(got.ToArray(), []int{7, 8, 9}) {
+			t.Errorf("gob Decode() = %v, %v", got, err)
+		}
+		if v, err := sub.(driver.Valuer).Value(); err != nil || v != "[7,8,9]" {
+			t.Errorf("Value() = %v, %v, want [7,8,9]", v, err)
+		}
+		if err := sub.(sql.Scanner).Scan("{1}"); err != nil || !reflect.DeepEqual(list.ToArray(), []int{1, 1, 4, 5}) {
+			t.Errorf("Scan() = %v, list = %v", err, list)
+		}
+	}
+}
@@ -0,0 +1,509 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"fmt"
+	"github.com/yzrzr/go-util/constraints"
+	"strings"
+)
+
+// NewTreeMap 创建一个基于红黑树的 SortedMap，键按照 less 排序
+// 值的比较使用 DefaultEqualFunc
+func NewTreeMap[K comparable, V any](less SortLess[K]) SortedMap[K, V] {
+	return newTreeMap[K, V](less)
+}
+
+// NewOrderedTreeMap 创建一个键为基础类型的 SortedMap
+// 参数 asc 表示是否为升序
+func NewOrderedTreeMap[K constraints.Ordered, V any](asc bool) SortedMap[K, V] {
+	return newTreeMap[K, V](SortLessOrdered[K](asc))
+}
+
+// NewComparableTreeMap 创建一个键实现了 constraints.Comparable 接口的 SortedMap
+// 参数 asc 表示是否为升序
+func NewComparableTreeMap[K interface {
+	comparable
+	constraints.Comparable[K]
+}, V any](asc bool) SortedMap[K, V] {
+	return newTreeMap[K, V](SortLessComparable[K](asc))
+}
+
+func newTreeMap[K comparable, V any](less SortLess[K]) *treeMap[K, V] {
+	return &treeMap[K, V]{
+		tree: newRBTree[K, V](less),
+		comparator: AnyEqualComparableFunc[V](func(v1, v2 V) bool {
+			return DefaultEqualFunc().Equal(v1, v2)
+		}),
+	}
+}
+
+// treeMap 红黑树实现的 SortedMap
+// 范围视图与原 Map 共享同一棵树，通过 lo、hi 限定键的范围
+type treeMap[K comparable, V any] struct {
+	tree       *rbTree[K, V]
+	comparator constraints.EqualComparator[V]
+	zeroVal    V
+
+	// lo hi 范围视图的下界和上界，hasLo、hasHi 为 false 时表示没有边界
+	lo, hi                   K
+	hasLo, hasHi             bool
+	loInclusive, hiInclusive bool
+}
+
+func (m *treeMap[K, V]) Size() int {
+	if !m.hasLo && !m.hasHi {
+		return m.tree.size
+	}
+	var cnt int
+	for n := m.firstNode(); n != nil; n = m.nextNode(n) {
+		cnt++
+	}
+	return cnt
+}
+
+func (m *treeMap[K, V]) IsEmpty() bool {
+	return m.firstNode() == nil
+}
+
+func (m *treeMap[K, V]) Get(k K) (V, bool) {
+	if n := m.getNode(k); n != nil {
+		return n.value, true
+	}
+	return m.zeroVal, false
+}
+
+func (m *treeMap[K, V]) GetOrDefault(k K, defaultValue V) V {
+	if v, ok := m.Get(k); ok {
+		return v
+	}
+	return defaultValue
+}
+
+func (m *treeMap[K, V]) Put(k K, v V) (V, bool) {
+	if !m.inRange(k) {
+		return m.zeroVal, false
+	}
+	if n, ok := m.tree.insert(k, v); ok {
+		return n.SetValue(v), true
+	}
+	return m.zeroVal, false
+}
+
+func (m *treeMap[K, V]) PutIfAbsent(k K, v V) (V, bool) {
+	if !m.inRange(k) {
+		return m.zeroVal, false
+	}
+	n, ok := m.tree.insert(k, v)
+	return n.value, ok
+}
+
+func (m *treeMap[K, V]) PutAll(o Map[K, V]) {
+	_ = o.ForEach(func(k K, v V) error {
+		m.Put(k, v)
+		return nil
+	})
+}
+
+func (m *treeMap[K, V]) Remove(k K) (V, bool) {
+	n := m.getNode(k)
+	if n == nil {
+		return m.zeroVal, false
+	}
+	m.tree.delete(n)
+	return n.value, true
+}
+
+func (m *treeMap[K, V]) ContainsKey(k K) bool {
+	return m.getNode(k) != nil
+}
+
+func (m *treeMap[K, V]) ContainsValue(v V) bool {
+	for n := m.firstNode(); n != nil; n = m.nextNode(n) {
+		if m.comparator.Equal(v, n.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *treeMap[K, V]) Compute(k K, remapping func(k K, old V, exists bool) (V, bool)) (V, bool) {
+	if !m.inRange(k) {
+		return m.zeroVal, false
+	}
+	n := m.tree.find(k)
+	old := m.zeroVal
+	if n != nil {
+		old = n.value
+	}
+	v, ok := remapping(k, old, n != nil)
+	if !ok {
+		if n != nil {
+			m.tree.delete(n)
+		}
+		return m.zeroVal, false
+	}
+	if n != nil {
+		n.value = v
+	} else {
+		m.tree.insert(k, v)
+	}
+	return v, true
+}
+
+func (m *treeMap[K, V]) ComputeIfAbsent(k K, mapping func(k K) V) V {
+	if !m.inRange(k) {
+		return m.zeroVal
+	}
+	if n := m.tree.find(k); n != nil {
+		return n.value
+	}
+	v := mapping(k)
+	m.tree.insert(k, v)
+	return v
+}
+
+func (m *treeMap[K, V]) Merge(k K, v V, remapping func(old, v V) (V, bool)) (V, bool) {
+	return m.Compute(k, func(k K, old V, exists bool) (V, bool) {
+		if !exists {
+			return v, true
+		}
+		return remapping(old, v)
+	})
+}
+
+func (m *treeMap[K, V]) ForEach(f BiConsumer[K, V]) error {
+	for n := m.firstNode(); n != nil; {
+		next := m.nextNode(n)
+		if err := f(n.key, n.value); err != nil {
+			return err
+		}
+		n = next
+	}
+	return nil
+}
+
+func (m *treeMap[K, V]) KeySet() Set[K] {
+	return newKeySetView[K, V](m)
+}
+
+func (m *treeMap[K, V]) Values() Collection[V] {
+	return newValuesView[K, V](m)
+}
+
+func (m *treeMap[K, V]) EntrySet() Set[Entry[K, V]] {
+	return newEntrySetView[K, V](m)
+}
+
+func (m *treeMap[K, V]) Clear() {
+	if !m.hasLo && !m.hasHi {
+		m.tree.clear()
+		return
+	}
+	for n := m.firstNode(); n != nil; {
+		next := m.nextNode(n)
+		m.tree.delete(n)
+		n = next
+	}
+}
+
+func (m *treeMap[K, V]) FirstKey() (K, bool) {
+	return m.key(m.firstNode())
+}
+
+func (m *treeMap[K, V]) LastKey() (K, bool) {
+	return m.key(m.lastNode())
+}
+
+func (m *treeMap[K, V]) FloorKey(k K) (K, bool) {
+	return m.key(m.floorNode(k, true))
+}
+
+func (m *treeMap[K, V]) CeilingKey(k K) (K, bool) {
+	return m.key(m.ceilingNode(k, true))
+}
+
+func (m *treeMap[K, V]) HigherKey(k K) (K, bool) {
+	return m.key(m.ceilingNode(k, false))
+}
+
+func (m *treeMap[K, V]) LowerKey(k K) (K, bool) {
+	return m.key(m.floorNode(k, false))
+}
+
+func (m *treeMap[K, V]) HeadMap(toKey K, inclusive bool) SortedMap[K, V] {
+	return m.subMap(false, m.zeroKey(), false, true, toKey, inclusive)
+}
+
+func (m *treeMap[K, V]) TailMap(fromKey K, inclusive bool) SortedMap[K, V] {
+	return m.subMap(true, fromKey, inclusive, false, m.zeroKey(), false)
+}
+
+func (m *treeMap[K, V]) SubMap(fromKey K, fromInclusive bool, toKey K, toInclusive bool) SortedMap[K, V] {
+	return m.subMap(true, fromKey, fromInclusive, true, toKey, toInclusive)
+}
+
+func (m *treeMap[K, V]) DescendingIterator() Iterator[Entry[K, V]] {
+	return newTreeIterator[K, V, Entry[K, V]](m, true, func(n *treeNode[K, V]) Entry[K, V] {
+		return n
+	})
+}
+
+func (m *treeMap[K, V]) String() string {
+	build := strings.Builder{}
+	build.WriteString("map[")
+	for n := m.firstNode(); n != nil; n = m.nextNode(n) {
+		build.WriteString(fmt.Sprintf("%v:%v", n.key, n.value))
+		if m.nextNode(n) != nil {
+			build.WriteByte(' ')
+		}
+	}
+	build.WriteByte(']')
+	return build.String()
+}
+
+func (m *treeMap[K, V]) entries() []Entry[K, V] {
+	var res []Entry[K, V]
+	for n := m.firstNode(); n != nil; n = m.nextNode(n) {
+		res = append(res, n)
+	}
+	return res
+}
+
+func (m *treeMap[K, V]) peek(k K) (V, bool) {
+	return m.Get(k)
+}
+
+func (m *treeMap[K, V]) valueComparator() constraints.EqualComparator[V] {
+	return m.comparator
+}
+
+// subMap 创建范围视图，新的范围是当前范围和指定范围的交集
+func (m *treeMap[K, V]) subMap(hasFrom bool, from K, fromInclusive bool, hasTo bool, to K, toInclusive bool) *treeMap[K, V] {
+	sub := *m
+	less := m.tree.less
+	if hasFrom {
+		if !m.hasLo || less(m.lo, from) {
+			sub.lo, sub.loInclusive = from, fromInclusive
+		} else if !less(from, m.lo) {
+			sub.loInclusive = m.loInclusive && fromInclusive
+		}
+		sub.hasLo = true
+	}
+	if hasTo {
+		if !m.hasHi || less(to, m.hi) {
+			sub.hi, sub.hiInclusive = to, toInclusive
+		} else if !less(m.hi, to) {
+			sub.hiInclusive = m.hiInclusive && toInclusive
+		}
+		sub.hasHi = true
+	}
+	return &sub
+}
+
+func (m *treeMap[K, V]) zeroKey() (k K) {
+	return
+}
+
+func (m *treeMap[K, V]) key(n *treeNode[K, V]) (K, bool) {
+	if n == nil {
+		return m.zeroKey(), false
+	}
+	return n.key, true
+}
+
+func (m *treeMap[K, V]) tooLow(k K) bool {
+	if !m.hasLo {
+		return false
+	}
+	return m.tree.less(k, m.lo) || (!m.loInclusive && !m.tree.less(m.lo, k))
+}
+
+func (m *treeMap[K, V]) tooHigh(k K) bool {
+	if !m.hasHi {
+		return false
+	}
+	return m.tree.less(m.hi, k) || (!m.hiInclusive && !m.tree.less(k, m.hi))
+}
+
+func (m *treeMap[K, V]) inRange(k K) bool {
+	return !m.tooLow(k) && !m.tooHigh(k)
+}
+
+// getNode 返回范围内键对应的节点，不存在返回 nil
+func (m *treeMap[K, V]) getNode(k K) *treeNode[K, V] {
+	if !m.inRange(k) {
+		return nil
+	}
+	return m.tree.find(k)
+}
+
+// firstNode 返回范围内的第一个节点，不存在返回 nil
+func (m *treeMap[K, V]) firstNode() *treeNode[K, V] {
+	var n *treeNode[K, V]
+	if m.hasLo {
+		n = m.tree.ceiling(m.lo, m.loInclusive)
+	} else {
+		n = m.tree.first()
+	}
+	if n == nil || m.tooHigh(n.key) {
+		return nil
+	}
+	return n
+}
+
+// lastNode 返回范围内的最后一个节点，不存在返回 nil
+func (m *treeMap[K, V]) lastNode() *treeNode[K, V] {
+	var n *treeNode[K, V]
+	if m.hasHi {
+		n = m.tree.floor(m.hi, m.hiInclusive)
+	} else {
+		n = m.tree.last()
+	}
+	if n == nil || m.tooLow(n.key) {
+		return nil
+	}
+	return n
+}
+
+// ceilingNode 返回范围内大于等于 k 的最小节点，inclusive 为 false 时返回大于 k 的最小节点
+func (m *treeMap[K, V]) ceilingNode(k K, inclusive bool) *treeNode[K, V] {
+	if m.tooLow(k) {
+		return m.firstNode()
+	}
+	n := m.tree.ceiling(k, inclusive)
+	if n == nil || m.tooHigh(n.key) {
+		return nil
+	}
+	return n
+}
+
+// floorNode 返回范围内小于等于 k 的最大节点，inclusive 为 false 时返回小于 k 的最大节点
+func (m *treeMap[K, V]) floorNode(k K, inclusive bool) *treeNode[K, V] {
+	if m.tooHigh(k) {
+		return m.lastNode()
+	}
+	n := m.tree.floor(k, inclusive)
+	if n == nil || m.tooLow(n.key) {
+		return nil
+	}
+	return n
+}
+
+// nextNode 返回范围内 n 的后继节点
+func (m *treeMap[K, V]) nextNode(n *treeNode[K, V]) *treeNode[K, V] {
+	s := m.tree.successor(n)
+	if s == nil || m.tooHigh(s.key) {
+		return nil
+	}
+	return s
+}
+
+// prevNode 返回范围内 n 的前驱节点
+func (m *treeMap[K, V]) prevNode(n *treeNode[K, V]) *treeNode[K, V] {
+	p := m.tree.predecessor(n)
+	if p == nil || m.tooLow(p.key) {
+		return nil
+	}
+	return p
+}
+
+func newTreeIterator[K comparable, V any, E any](m *treeMap[K, V], descending bool, project func(n *treeNode[K, V]) E) Iterator[E] {
+	it := &treeIterator[K, V, E]{
+		m:          m,
+		descending: descending,
+		project:    project,
+	}
+	if descending {
+		it.next = m.lastNode()
+	} else {
+		it.next = m.firstNode()
+	}
+	return it
+}
+
+// treeIterator 直接遍历红黑树节点的迭代器，E 为迭代的元素类型
+type treeIterator[K comparable, V any, E any] struct {
+	// next 下一次调用 Next() 方法返回的节点
+	// lastRet 上一次调用 Next() 方法返回的节点
+	next, lastRet *treeNode[K, V]
+
+	descending bool
+	isClose    bool
+	m          *treeMap[K, V]
+	project    func(n *treeNode[K, V]) E
+}
+
+func (t *treeIterator[K, V, E]) HasNext() bool {
+	return t.next != nil && t.isClose == false
+}
+
+func (t *treeIterator[K, V, E]) Next() (e E, err error) {
+	if t.isClose {
+		err = ErrIteratorClose
+		return
+	}
+	n := t.next
+	if n == nil {
+		err = ErrNoSuchElement
+		return
+	}
+	if t.descending {
+		t.next = t.m.prevNode(n)
+	} else {
+		t.next = t.m.nextNode(n)
+	}
+	t.lastRet = n
+	return t.project(n), nil
+}
+
+func (t *treeIterator[K, V, E]) Remove() error {
+	if t.isClose {
+		return ErrIteratorClose
+	}
+	if t.lastRet == nil {
+		return ErrIllegalState
+	}
+	// 删除节点不会移动其他节点，next 依然有效
+	t.m.tree.delete(t.lastRet)
+	t.lastRet = nil
+	return nil
+}
+
+func (t *treeIterator[K, V, E]) ForEachRemaining(action Consumer[E]) error {
+	if t.isClose {
+		return ErrIteratorClose
+	}
+	for t.HasNext() {
+		e, err := t.Next()
+		if err != nil {
+			return err
+		}
+		if err = action(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *treeIterator[K, V, E]) Close() {
+	t.isClose = true
+	t.next = nil
+	t.lastRet = nil
+}
@@ -0,0 +1,184 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// checkRBTree 检查红黑树的性质，返回黑高
+func checkRBTree[K comparable, V any](t *testing.T, tree *rbTree[K, V], n *treeNode[K, V]) int {
+	if n == tree.sentinel {
+		return 1
+	}
+	if n.red && (n.left.red || n.right.red) {
+		t.Fatalf("red node %v has red child", n.key)
+	}
+	if n.left != tree.sentinel && (n.left.parent != n || !tree.less(n.left.key, n.key)) {
+		t.Fatalf("invalid left child of %v", n.key)
+	}
+	if n.right != tree.sentinel && (n.right.parent != n || !tree.less(n.key, n.right.key)) {
+		t.Fatalf("invalid right child of %v", n.key)
+	}
+	l, r := checkRBTree(t, tree, n.left), checkRBTree(t, tree, n.right)
+	if l != r {
+		t.Fatalf("black height mismatch at %v: %d != %d", n.key, l, r)
+	}
+	if n.red {
+		return l
+	}
+	return l + 1
+}
+
+func Test_treeMap_random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	m := newTreeMap[int, int](SortLessOrdered[int](true))
+	ref := make(map[int]int)
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(500)
+		if r.Intn(3) == 0 {
+			_, ok := m.Remove(k)
+			_, want := ref[k]
+			if ok != want {
+				t.Fatalf("Remove(%d) = %v, want %v", k, ok, want)
+			}
+			delete(ref, k)
+		} else {
+			m.Put(k, i)
+			ref[k] = i
+		}
+		if m.tree.root.red {
+			t.Fatal("root is red")
+		}
+		checkRBTree(t, m.tree, m.tree.root)
+	}
+	if m.Size() != len(ref) {
+		t.Fatalf("Size() = %d, want %d", m.Size(), len(ref))
+	}
+	var keys []int
+	for k := range ref {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	if got := m.KeySet().ToArray(); !reflect.DeepEqual(got, keys) {
+		t.Errorf("KeySet() = %v, want %v", got, keys)
+	}
+	for k, v := range ref {
+		if got, ok := m.Get(k); !ok || got != v {
+			t.Errorf("Get(%d) = %v, %v, want %v, true", k, got, ok, v)
+		}
+	}
+}
+
+func Test_treeMap_navigation(t *testing.T) {
+	m := NewOrderedTreeMap[int, string](true)
+	for _, k := range []int{50, 10, 40, 20, 30} {
+		m.Put(k, "v")
+	}
+	tests := []struct {
+		name string
+		f    func(k int) (int, bool)
+		k    int
+		want int
+		ok   bool
+	}{
+		{"FloorKey", m.FloorKey, 25, 20, true},
+		{"FloorKey", m.FloorKey, 20, 20, true},
+		{"FloorKey", m.FloorKey, 5, 0, false},
+		{"CeilingKey", m.CeilingKey, 25, 30, true},
+		{"CeilingKey", m.CeilingKey, 30, 30, true},
+		{"CeilingKey", m.CeilingKey, 55, 0, false},
+		{"HigherKey", m.HigherKey, 30, 40, true},
+		{"HigherKey", m.HigherKey, 50, 0, false},
+		{"LowerKey", m.LowerKey, 30, 20, true},
+		{"LowerKey", m.LowerKey, 10, 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := tt.f(tt.k); got != tt.want || ok != tt.ok {
+			t.Errorf("%s(%d) = %v, %v, want %v, %v", tt.name, tt.k, got, ok, tt.want, tt.ok)
+		}
+	}
+	if k, _ := m.FirstKey(); k != 10 {
+		t.Errorf("FirstKey() = %v, want 10", k)
+	}
+	if k, _ := m.LastKey(); k != 50 {
+		t.Errorf("LastKey() = %v, want 50", k)
+	}
+	var desc []int
+	it := m.DescendingIterator()
+	for it.HasNext() {
+		e, _ := it.Next()
+		desc = append(desc, e.Key())
+		if e.Key() == 30 {
+			_ = it.Remove()
+		}
+	}
+	if !reflect.DeepEqual(desc, []int{50, 40, 30, 20, 10}) {
+		t.Errorf("DescendingIterator() = %v, want %v", desc, []int{50, 40, 30, 20, 10})
+	}
+	if got := m.(interface{ String() string }).String(); got != "map[10:v 20:v 40:v 50:v]" {
+		t.Errorf("String() = %v, want map[10:v 20:v 40:v 50:v]", got)
+	}
+}
+
+func Test_treeMap_subMap(t *testing.T) {
+	m := NewOrderedTreeMap[int, int](true)
+	for i := 1; i <= 10; i++ {
+		m.Put(i, i*i)
+	}
+	sub := m.SubMap(3, true, 7, false)
+	if got := sub.KeySet().ToArray(); !reflect.DeepEqual(got, []int{3, 4, 5, 6}) {
+		t.Errorf("SubMap() = %v, want %v", got, []int{3, 4, 5, 6})
+	}
+	if sub.ContainsKey(7) || sub.Size() != 4 {
+		t.Error("SubMap() range mismatch")
+	}
+	if _, ok := sub.Put(8, 0); ok || m.Size() != 10 {
+		t.Error("Put() out of range should be ignored")
+	}
+	sub.Remove(4)
+	if m.ContainsKey(4) {
+		t.Error("SubMap().Remove() did not write through")
+	}
+	head := m.HeadMap(5, true).TailMap(2, false)
+	if got := head.KeySet().ToArray(); !reflect.DeepEqual(got, []int{3, 5}) {
+		t.Errorf("HeadMap().TailMap() = %v, want %v", got, []int{3, 5})
+	}
+	if k, ok := head.CeilingKey(0); !ok || k != 3 {
+		t.Errorf("CeilingKey() = %v, %v, want 3, true", k, ok)
+	}
+	if k, ok := head.FloorKey(100); !ok || k != 5 {
+		t.Errorf("FloorKey() = %v, %v, want 5, true", k, ok)
+	}
+	// 嵌套视图不能超出外层视图的范围
+	if got := head.TailMap(0, true).KeySet().ToArray(); !reflect.DeepEqual(got, []int{3, 5}) {
+		t.Errorf("TailMap() = %v, want %v", got, []int{3, 5})
+	}
+	m.Put(4, 16)
+	if got := head.KeySet().ToArray(); !reflect.DeepEqual(got, []int{3, 4, 5}) {
+		t.Errorf("view = %v, want %v", got, []int{3, 4, 5})
+	}
+	m.TailMap(8, true).Clear()
+	if got := m.KeySet().ToArray(); !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("TailMap().Clear() = %v, want %v", got, []int{1, 2, 3, 4, 5, 6, 7})
+	}
+}
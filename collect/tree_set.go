@@ -0,0 +1,123 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import "github.com/yzrzr/go-util/constraints"
+
+// NewTreeSet 创建一个基于红黑树的 SortedSet，元素按照 less 排序
+func NewTreeSet[E comparable](less SortLess[E]) SortedSet[E] {
+	return newTreeSet[E](newTreeMap[E, struct{}](less))
+}
+
+// NewOrderedTreeSet 创建一个基础类型的 SortedSet
+// 参数 asc 表示是否为升序
+func NewOrderedTreeSet[E constraints.Ordered](asc bool) SortedSet[E] {
+	return NewTreeSet[E](SortLessOrdered[E](asc))
+}
+
+// NewComparableTreeSet 创建一个元素实现了 constraints.Comparable 接口的 SortedSet
+// 参数 asc 表示是否为升序
+func NewComparableTreeSet[E interface {
+	comparable
+	constraints.Comparable[E]
+}](asc bool) SortedSet[E] {
+	return NewTreeSet[E](SortLessComparable[E](asc))
+}
+
+func newTreeSet[E comparable](m *treeMap[E, struct{}]) *treeSet[E] {
+	return &treeSet[E]{
+		mapSet: newMapSet[E](m),
+		tm:     m,
+	}
+}
+
+type treeSet[E comparable] struct {
+	*mapSet[E]
+	tm *treeMap[E, struct{}]
+}
+
+func (t *treeSet[E]) Iterator() Iterator[E] {
+	return newTreeIterator[E, struct{}, E](t.tm, false, t.project)
+}
+
+// Add 添加元素，超出视图范围的元素会被忽略并返回false
+func (t *treeSet[E]) Add(e E) bool {
+	if !t.tm.inRange(e) {
+		return false
+	}
+	return t.mapSet.Add(e)
+}
+
+func (t *treeSet[E]) AddAll(c Collection[E]) {
+	_ = c.ForEach(func(e E) error {
+		t.Add(e)
+		return nil
+	})
+}
+
+func (t *treeSet[E]) Equals(c Collection[E]) bool {
+	if Collection[E](t) == c {
+		return true
+	}
+	return t.mapSet.Equals(c)
+}
+
+func (t *treeSet[E]) First() (E, bool) {
+	return t.tm.FirstKey()
+}
+
+func (t *treeSet[E]) Last() (E, bool) {
+	return t.tm.LastKey()
+}
+
+func (t *treeSet[E]) Floor(e E) (E, bool) {
+	return t.tm.FloorKey(e)
+}
+
+func (t *treeSet[E]) Ceiling(e E) (E, bool) {
+	return t.tm.CeilingKey(e)
+}
+
+func (t *treeSet[E]) Higher(e E) (E, bool) {
+	return t.tm.HigherKey(e)
+}
+
+func (t *treeSet[E]) Lower(e E) (E, bool) {
+	return t.tm.LowerKey(e)
+}
+
+func (t *treeSet[E]) HeadSet(toElement E, inclusive bool) SortedSet[E] {
+	return newTreeSet[E](t.tm.HeadMap(toElement, inclusive).(*treeMap[E, struct{}]))
+}
+
+func (t *treeSet[E]) TailSet(fromElement E, inclusive bool) SortedSet[E] {
+	return newTreeSet[E](t.tm.TailMap(fromElement, inclusive).(*treeMap[E, struct{}]))
+}
+
+func (t *treeSet[E]) SubSet(fromElement E, fromInclusive bool, toElement E, toInclusive bool) SortedSet[E] {
+	return newTreeSet[E](t.tm.SubMap(fromElement, fromInclusive, toElement, toInclusive).(*treeMap[E, struct{}]))
+}
+
+func (t *treeSet[E]) DescendingIterator() Iterator[E] {
+	return newTreeIterator[E, struct{}, E](t.tm, true, t.project)
+}
+
+func (t *treeSet[E]) project(n *treeNode[E, struct{}]) E {
+	return n.key
+}
@@ -0,0 +1,123 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collect
+
+import (
+	"reflect"
+	"testing"
+)
+
+type treeId struct {
+	id int
+}
+
+func (t treeId) Compare(v treeId) int {
+	if t.id < v.id {
+		return -1
+	} else if t.id > v.id {
+		return 1
+	}
+	return 0
+}
+
+func Test_treeSet(t *testing.T) {
+	set := NewOrderedTreeSet[int](true)
+	for _, v := range []int{5, 1, 9, 3, 7, 3} {
+		set.Add(v)
+	}
+	if got := set.ToArray(); !reflect.DeepEqual(got, []int{1, 3, 5, 7, 9}) {
+		t.Errorf("ToArray() = %v, want %v", got, []int{1, 3, 5, 7, 9})
+	}
+	if got, want := set.(interface{ String() string }).String(), "[1 3 5 7 9]"; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+	if e, ok := set.Floor(4); !ok || e != 3 {
+		t.Errorf("Floor(4) = %v, %v, want 3, true", e, ok)
+	}
+	if e, ok := set.Ceiling(4); !ok || e != 5 {
+		t.Errorf("Ceiling(4) = %v, %v, want 5, true", e, ok)
+	}
+	if e, ok := set.Higher(9); ok {
+		t.Errorf("Higher(9) = %v, %v, want 0, false", e, ok)
+	}
+	if e, ok := set.Lower(1); ok {
+		t.Errorf("Lower(1) = %v, %v, want 0, false", e, ok)
+	}
+	if got := iteratorValues(t, set.DescendingIterator()); !reflect.DeepEqual(got, []int{9, 7, 5, 3, 1}) {
+		t.Errorf("DescendingIterator() = %v, want %v", got, []int{9, 7, 5, 3, 1})
+	}
+	tail := set.TailSet(5, true)
+	if tail.Add(2) {
+		t.Error("TailSet().Add(2) = true, want false")
+	}
+	if !tail.Add(6) || !set.Contains(6) {
+		t.Error("TailSet().Add(6) did not write through")
+	}
+	if got := set.SubSet(3, false, 9, false).ToArray(); !reflect.DeepEqual(got, []int{5, 6, 7}) {
+		t.Errorf("SubSet() = %v, want %v", got, []int{5, 6, 7})
+	}
+	if got := set.HeadSet(5, false).ToArray(); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("HeadSet() = %v, want %v", got, []int{1, 3})
+	}
+	it := set.Iterator()
+	for it.HasNext() {
+		if v, _ := it.Next(); v%3 == 0 {
+			if err := it.Remove(); err != nil {
+				t.Errorf("Remove() = %v, want nil", err)
+			}
+		}
+	}
+	if !set.Equals(SetOf(1, 5, 7)) {
+		t.Errorf("set = %v, want %v", set, []int{1, 5, 7})
+	}
+	if e, _ := tail.First(); e != 5 {
+		t.Errorf("TailSet().First() = %v, want 5", e)
+	}
+}
+
+func Test_treeSet_comparable(t *testing.T) {
+	set := NewComparableTreeSet[treeId](false)
+	for _, id := range []int{2, 8, 4} {
+		set.Add(treeId{id})
+	}
+	if e, _ := set.First(); e.id != 8 {
+		t.Errorf("First() = %v, want 8", e.id)
+	}
+	if e, _ := set.Last(); e.id != 2 {
+		t.Errorf("Last() = %v, want 2", e.id)
+	}
+}
+
+func Test_treeSet_All(t *testing.T) {
+	set := NewOrderedTreeSet[int](true)
+	set.AddAll(wrapArrayList([]int{5, 1, 4, 2, 3}))
+	var got []int
+	for e := range set.TailSet(2, true).All() {
+		if e%2 == 0 {
+			set.Remove(e)
+		}
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, []int{2, 3, 4, 5}) {
+		t.Errorf("All() = %v, want %v", got, []int{2, 3, 4, 5})
+	}
+	if !set.Equals(SetOf(1, 3, 5)) {
+		t.Errorf("set = %v, want %v", set, []int{1, 3, 5})
+	}
+}
@@ -20,6 +20,11 @@ type Complex interface {
 	~complex64 | ~complex128
 }
 
+// Number 可以进行四则运算的数值类型
+type Number interface {
+	Integer | Float
+}
+
 type Ordered interface {
 	Integer | Float | ~string
 }
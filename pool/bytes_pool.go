@@ -0,0 +1,197 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pool
+
+import (
+	"bytes"
+	"math/bits"
+	"sync"
+)
+
+// BytesPoolConfig BytesPool 和 BufferPool 的配置
+type BytesPoolConfig struct {
+	// MinSize 最小的 size class，向上取整为 2 的幂，小于等于 0 时使用 64
+	MinSize int
+	// MaxSize 最大的 size class，向上取整为 2 的幂，超过 MaxSize 的切片不会被复用，小于等于 0 时使用 1MB
+	MaxSize int
+	// Observer 所有 size class 共享的生命周期事件观察者，为 nil 时不通知
+	Observer Observer
+}
+
+var DefaultBytesPoolConfig = BytesPoolConfig{
+	MinSize: 64,
+	MaxSize: 1 << 20,
+}
+
+// sizeClasses 按照 2 的幂划分的 size class，第 i 个 size class 的大小为 1 << (minShift + i)
+type sizeClasses struct {
+	minShift, maxShift int
+}
+
+func newSizeClasses(config BytesPoolConfig) sizeClasses {
+	if config.MinSize <= 0 {
+		config.MinSize = DefaultBytesPoolConfig.MinSize
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultBytesPoolConfig.MaxSize
+	}
+	s := sizeClasses{
+		minShift: bits.Len(uint(config.MinSize - 1)),
+		maxShift: bits.Len(uint(config.MaxSize - 1)),
+	}
+	if s.maxShift < s.minShift {
+		s.maxShift = s.minShift
+	}
+	return s
+}
+
+func (s sizeClasses) count() int {
+	return s.maxShift - s.minShift + 1
+}
+
+func (s sizeClasses) size(i int) int {
+	return 1 << (s.minShift + i)
+}
+
+// getIndex 返回容量不小于 n 的最小 size class，n 超过最大的 size class 时返回 -1
+func (s sizeClasses) getIndex(n int) int {
+	shift := bits.Len(uint(max(n, 1) - 1))
+	if shift > s.maxShift {
+		return -1
+	}
+	return max(shift-s.minShift, 0)
+}
+
+// putIndex 返回容量不超过 c 的最大 size class，保证从该 size class 借出的切片容量不小于 size(i)
+// c 小于最小的 size class 或者超过最大的 size class 时返回 -1
+func (s sizeClasses) putIndex(c int) int {
+	if c <= 0 {
+		return -1
+	}
+	shift := bits.Len(uint(c)) - 1
+	if shift < s.minShift || c > s.size(s.count()-1) {
+		return -1
+	}
+	return shift - s.minShift
+}
+
+// BytesPool 按照 2 的幂划分 size class 的字节切片池，每个 size class 使用单独的 Pool
+type BytesPool struct {
+	classes sizeClasses
+	pools   []*Pool[*[]byte]
+	// boxes 复用 Get 之后空出来的 *[]byte，避免 Put 时每次都分配新的指针
+	boxes sync.Pool
+}
+
+func NewBytesPool(config BytesPoolConfig) *BytesPool {
+	classes := newSizeClasses(config)
+	p := &BytesPool{
+		classes: classes,
+		pools:   make([]*Pool[*[]byte], classes.count()),
+	}
+	for i := range p.pools {
+		size := classes.size(i)
+		p.pools[i] = NewPool(func() *[]byte {
+			b := make([]byte, size)
+			return &b
+		}, WithObserver[*[]byte](config.Observer))
+	}
+	return p
+}
+
+// Get 返回长度为 n、容量不小于 n 的切片，切片的内容是未定义的
+// n 超过最大的 size class 时直接分配内存
+func (p *BytesPool) Get(n int) []byte {
+	i := p.classes.getIndex(n)
+	if i < 0 {
+		return make([]byte, n)
+	}
+	box := p.pools[i].Get()
+	b := (*box)[:n]
+	*box = nil
+	p.boxes.Put(box)
+	return b
+}
+
+// Put 将切片归还到容量对应的 size class，容量小于最小的 size class 或者超过最大的 size class 的切片会被丢弃
+func (p *BytesPool) Put(b []byte) {
+	if i := p.classes.putIndex(cap(b)); i >= 0 {
+		box, _ := p.boxes.Get().(*[]byte)
+		if box == nil {
+			box = new([]byte)
+		}
+		*box = b[:cap(b)]
+		p.pools[i].Put(box)
+	}
+}
+
+// Stats 返回所有 size class 统计信息的汇总
+func (p *BytesPool) Stats() Stats {
+	var s Stats
+	for _, pool := range p.pools {
+		s.merge(pool.Stats())
+	}
+	return s
+}
+
+// BufferPool 按照 2 的幂划分 size class 的 *bytes.Buffer 池，归还的 Buffer 会被 Reset
+type BufferPool struct {
+	classes sizeClasses
+	pools   []*Pool[*bytes.Buffer]
+}
+
+func NewBufferPool(config BytesPoolConfig) *BufferPool {
+	classes := newSizeClasses(config)
+	p := &BufferPool{
+		classes: classes,
+		pools:   make([]*Pool[*bytes.Buffer], classes.count()),
+	}
+	for i := range p.pools {
+		size := classes.size(i)
+		p.pools[i] = NewPool(func() *bytes.Buffer {
+			return bytes.NewBuffer(make([]byte, 0, size))
+		}, WithObserver[*bytes.Buffer](config.Observer))
+	}
+	return p
+}
+
+// Get 返回容量不小于 n 的空 Buffer，n 超过最大的 size class 时直接分配内存
+func (p *BufferPool) Get(n int) *bytes.Buffer {
+	i := p.classes.getIndex(n)
+	if i < 0 {
+		return bytes.NewBuffer(make([]byte, 0, n))
+	}
+	return p.pools[i].Get()
+}
+
+// Put 将 Buffer 归还到容量对应的 size class，Buffer 写入数据后容量可能增长，超过最大的 size class 的 Buffer 会被丢弃
+func (p *BufferPool) Put(buf *bytes.Buffer) {
+	if i := p.classes.putIndex(buf.Cap()); i >= 0 {
+		p.pools[i].Put(buf)
+	}
+}
+
+// Stats 返回所有 size class 统计信息的汇总
+func (p *BufferPool) Stats() Stats {
+	var s Stats
+	for _, pool := range p.pools {
+		s.merge(pool.Stats())
+	}
+	return s
+}
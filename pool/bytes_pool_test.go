@@ -0,0 +1,100 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pool
+
+import (
+	"testing"
+)
+
+func Test_sizeClasses(t *testing.T) {
+	s := newSizeClasses(BytesPoolConfig{MinSize: 60, MaxSize: 1000})
+	if s.count() != 5 || s.size(0) != 64 || s.size(4) != 1024 {
+		t.Fatalf("sizeClasses = %+v", s)
+	}
+	tests := []struct {
+		n, get, put int
+	}{
+		{0, 0, -1},
+		{1, 0, -1},
+		{64, 0, 0},
+		{65, 1, 0},
+		{127, 1, 0},
+		{128, 1, 1},
+		{1000, 4, 3},
+		{1024, 4, 4},
+		{1025, -1, -1},
+	}
+	for _, tt := range tests {
+		if got := s.getIndex(tt.n); got != tt.get {
+			t.Errorf("getIndex(%d) = %d, want %d", tt.n, got, tt.get)
+		}
+		if got := s.putIndex(tt.n); got != tt.put {
+			t.Errorf("putIndex(%d) = %d, want %d", tt.n, got, tt.put)
+		}
+	}
+}
+
+func TestBytesPool(t *testing.T) {
+	p := NewBytesPool(BytesPoolConfig{MinSize: 16, MaxSize: 256})
+	for _, n := range []int{0, 10, 16, 17, 100, 256, 300} {
+		b := p.Get(n)
+		if len(b) != n || cap(b) < n {
+			t.Errorf("Get(%d) len = %d, cap = %d", n, len(b), cap(b))
+		}
+		p.Put(b)
+	}
+	// 超过最大 size class 的切片不会被复用
+	if got := p.Stats(); got.Borrowed != 6 || got.Returned != 6 {
+		t.Errorf("Stats() = %+v", got)
+	}
+	p.Put(make([]byte, 8))
+	p.Put(make([]byte, 1024))
+	if got := p.Stats(); got.Returned != 6 {
+		t.Errorf("Stats() = %+v", got)
+	}
+}
+
+func TestBytesPool_allocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops objects randomly under -race")
+	}
+	p := NewBytesPool(DefaultBytesPoolConfig)
+	p.Put(p.Get(100))
+	if n := testing.AllocsPerRun(100, func() {
+		p.Put(p.Get(100))
+	}); n != 0 {
+		t.Errorf("Get() and Put() allocs = %v, want 0", n)
+	}
+}
+
+func TestBufferPool(t *testing.T) {
+	p := NewBufferPool(DefaultBytesPoolConfig)
+	buf := p.Get(100)
+	if buf.Cap() < 100 || buf.Len() != 0 {
+		t.Fatalf("Get(100) len = %d, cap = %d", buf.Len(), buf.Cap())
+	}
+	buf.WriteString("hello")
+	p.Put(buf)
+	if buf.Len() != 0 {
+		t.Errorf("Put() did not reset buffer, len = %d", buf.Len())
+	}
+	if buf := p.Get(2 << 20); buf.Cap() < 2<<20 {
+		t.Errorf("Get() cap = %d", buf.Cap())
+	}
+}
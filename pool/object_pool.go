@@ -0,0 +1,397 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pool
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrPoolClosed 对象池已经关闭
+	ErrPoolClosed = errors.New("pool is closed")
+	// ErrNotBorrowed 归还或者销毁的对象不是从对象池借出的，或者已经归还过
+	ErrNotBorrowed = errors.New("object is not borrowed from pool")
+)
+
+// PooledObjectFactory 管理对象池中对象的生命周期
+type PooledObjectFactory[T any] interface {
+	// OnCreate 创建一个新对象
+	OnCreate(ctx context.Context) (T, error)
+
+	// OnValidate 校验对象是否仍然可用，返回 false 时对象会被销毁
+	OnValidate(obj T) bool
+
+	// OnDestroy 销毁对象，释放对象持有的资源
+	OnDestroy(obj T)
+}
+
+// FactoryFunc 使用函数实现 PooledObjectFactory，Validate 为 nil 时对象总是可用，Destroy 为 nil 时不做任何处理
+type FactoryFunc[T any] struct {
+	Create   func(ctx context.Context) (T, error)
+	Validate func(obj T) bool
+	Destroy  func(obj T)
+}
+
+func (f FactoryFunc[T]) OnCreate(ctx context.Context) (T, error) {
+	return f.Create(ctx)
+}
+
+func (f FactoryFunc[T]) OnValidate(obj T) bool {
+	return f.Validate == nil || f.Validate(obj)
+}
+
+func (f FactoryFunc[T]) OnDestroy(obj T) {
+	if f.Destroy != nil {
+		f.Destroy(obj)
+	}
+}
+
+// ObjectPoolConfig 对象池配置
+type ObjectPoolConfig struct {
+	// MaxTotal 对象池最多同时存在的对象个数（空闲和借出的对象之和），小于等于 0 表示不限制
+	MaxTotal int
+	// MaxIdle 最多保留的空闲对象个数，超出的对象归还时被销毁，小于等于 0 表示与 MaxTotal 相同
+	MaxIdle int
+	// MinIdle 驱逐空闲对象时至少保留的空闲对象个数，不足时驱逐协程会补充创建对象
+	MinIdle int
+	// MaxWait Borrow 等待可用对象的最长时间，小于等于 0 表示只受 ctx 控制
+	MaxWait time.Duration
+	// TestOnBorrow 借出对象前使用 OnValidate 校验对象
+	TestOnBorrow bool
+	// TestOnReturn 归还对象时使用 OnValidate 校验对象
+	TestOnReturn bool
+	// IdleTimeout 对象空闲超过该时间后会被驱逐协程销毁，小于等于 0 表示不按照空闲时间驱逐
+	IdleTimeout time.Duration
+	// EvictionInterval 驱逐协程的运行间隔，小于等于 0 表示不启动驱逐协程
+	EvictionInterval time.Duration
+	// Observer 生命周期事件的观察者，为 nil 时不通知
+	Observer Observer
+}
+
+var DefaultObjectPoolConfig = ObjectPoolConfig{
+	MaxTotal:         8,
+	MaxIdle:          8,
+	TestOnBorrow:     true,
+	IdleTimeout:      30 * time.Minute,
+	EvictionInterval: time.Minute,
+}
+
+// ObjectPool 有界对象池，与 Pool 不同，池中的对象不会被 GC 回收，并且对象个数不会超过 MaxTotal
+type ObjectPool[T comparable] interface {
+	// Borrow 从对象池借出一个对象，没有空闲对象时创建新对象
+	// 对象个数达到 MaxTotal 时阻塞等待其它对象归还，直到 ctx 结束或者超过 MaxWait
+	Borrow(ctx context.Context) (T, error)
+
+	// Return 将借出的对象归还到对象池
+	Return(obj T) error
+
+	// Invalidate 销毁借出的对象，对象不再归还到对象池
+	Invalidate(obj T) error
+
+	// Close 关闭对象池并销毁所有空闲对象，之后归还的对象会直接销毁
+	Close()
+
+	// Stats 返回统计信息快照
+	Stats() Stats
+}
+
+// NewObjectPool 使用 factory 创建对象池，EvictionInterval 大于 0 时启动驱逐协程，使用完毕后需要调用 Close
+func NewObjectPool[T comparable](factory PooledObjectFactory[T], config ObjectPoolConfig) ObjectPool[T] {
+	if config.MaxIdle <= 0 || (config.MaxTotal > 0 && config.MaxIdle > config.MaxTotal) {
+		config.MaxIdle = config.MaxTotal
+	}
+	if config.MaxIdle > 0 && config.MinIdle > config.MaxIdle {
+		config.MinIdle = config.MaxIdle
+	}
+	p := &objectPool[T]{
+		factory: factory,
+		config:  config,
+		idle:    list.New(),
+		active:  make(map[T]struct{}),
+		waiters: list.New(),
+		stop:    make(chan struct{}),
+	}
+	p.stats.observer = config.Observer
+	if config.EvictionInterval > 0 {
+		p.wg.Add(1)
+		go p.evictLoop()
+	}
+	return p
+}
+
+// idleObject 空闲对象和它归还到对象池的时间
+type idleObject[T any] struct {
+	obj       T
+	idleSince time.Time
+}
+
+type objectPool[T comparable] struct {
+	factory PooledObjectFactory[T]
+	config  ObjectPoolConfig
+
+	mu sync.Mutex
+	// idle 空闲对象，最近归还的对象在队首，优先借出
+	idle *list.List
+	// active 借出的对象
+	active map[T]struct{}
+	// total 空闲、借出和正在创建的对象个数之和
+	total int
+	// waiters 等待可用对象的 Borrow 调用，元素类型为 chan struct{}
+	waiters *list.List
+	closed  bool
+
+	stop  chan struct{}
+	wg    sync.WaitGroup
+	stats statsCounter
+}
+
+func (p *objectPool[T]) Borrow(ctx context.Context) (T, error) {
+	var zero T
+	if p.config.MaxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.config.MaxWait)
+		defer cancel()
+	}
+	var waitStart time.Time
+	defer func() {
+		if !waitStart.IsZero() {
+			p.stats.recordWait(time.Since(waitStart))
+		}
+	}()
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return zero, ErrPoolClosed
+		}
+		if front := p.idle.Front(); front != nil {
+			obj := p.idle.Remove(front).(*idleObject[T]).obj
+			p.active[obj] = struct{}{}
+			p.mu.Unlock()
+			if p.config.TestOnBorrow && !p.factory.OnValidate(obj) {
+				p.Invalidate(obj)
+				continue
+			}
+			p.stats.record(EventBorrow)
+			return obj, nil
+		}
+		if p.config.MaxTotal <= 0 || p.total < p.config.MaxTotal {
+			p.total++
+			p.mu.Unlock()
+			obj, err := p.factory.OnCreate(ctx)
+			p.mu.Lock()
+			if err != nil {
+				p.total--
+				p.signal()
+				p.mu.Unlock()
+				return zero, err
+			}
+			p.active[obj] = struct{}{}
+			p.mu.Unlock()
+			p.stats.record(EventCreate)
+			p.stats.record(EventBorrow)
+			return obj, nil
+		}
+		ch := make(chan struct{}, 1)
+		waiter := p.waiters.PushBack(ch)
+		p.mu.Unlock()
+		if waitStart.IsZero() {
+			waitStart = time.Now()
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			p.mu.Lock()
+			p.waiters.Remove(waiter)
+			p.mu.Unlock()
+			// 超时的同时被唤醒，将唤醒传递给下一个等待者
+			select {
+			case <-ch:
+				p.mu.Lock()
+				p.signal()
+				p.mu.Unlock()
+			default:
+			}
+			return zero, ctx.Err()
+		}
+	}
+}
+
+func (p *objectPool[T]) Return(obj T) error {
+	p.mu.Lock()
+	if _, ok := p.active[obj]; !ok {
+		p.mu.Unlock()
+		return ErrNotBorrowed
+	}
+	delete(p.active, obj)
+	p.mu.Unlock()
+	p.stats.record(EventReturn)
+
+	valid := !p.config.TestOnReturn || p.factory.OnValidate(obj)
+	p.mu.Lock()
+	if valid && !p.closed && (p.config.MaxIdle <= 0 || p.idle.Len() < p.config.MaxIdle) {
+		p.idle.PushFront(&idleObject[T]{obj: obj, idleSince: time.Now()})
+		p.signal()
+		p.mu.Unlock()
+		return nil
+	}
+	p.total--
+	p.signal()
+	p.mu.Unlock()
+	p.destroy(obj)
+	return nil
+}
+
+func (p *objectPool[T]) Invalidate(obj T) error {
+	p.mu.Lock()
+	if _, ok := p.active[obj]; !ok {
+		p.mu.Unlock()
+		return ErrNotBorrowed
+	}
+	delete(p.active, obj)
+	p.total--
+	p.signal()
+	p.mu.Unlock()
+	p.destroy(obj)
+	return nil
+}
+
+func (p *objectPool[T]) Stats() Stats {
+	s := p.stats.snapshot()
+	p.mu.Lock()
+	s.Idle = p.idle.Len()
+	s.Active = len(p.active)
+	p.mu.Unlock()
+	return s
+}
+
+func (p *objectPool[T]) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	idle := p.drainIdle(func(*idleObject[T]) bool {
+		return true
+	})
+	for p.waiters.Len() > 0 {
+		p.signal()
+	}
+	p.mu.Unlock()
+	close(p.stop)
+	p.wg.Wait()
+	for _, obj := range idle {
+		p.destroy(obj)
+	}
+}
+
+// destroy 销毁已经从对象池中移除的对象
+func (p *objectPool[T]) destroy(obj T) {
+	p.factory.OnDestroy(obj)
+	p.stats.record(EventDestroy)
+}
+
+// signal 唤醒最早等待的 Borrow 调用，调用时需要持有 mu
+func (p *objectPool[T]) signal() {
+	if front := p.waiters.Front(); front != nil {
+		p.waiters.Remove(front).(chan struct{}) <- struct{}{}
+	}
+}
+
+// drainIdle 从最早归还的空闲对象开始，移除 evict 返回 true 的对象，遇到返回 false 的对象时停止，调用时需要持有 mu
+func (p *objectPool[T]) drainIdle(evict func(*idleObject[T]) bool) []T {
+	var res []T
+	for back := p.idle.Back(); back != nil; back = p.idle.Back() {
+		o := back.Value.(*idleObject[T])
+		if !evict(o) {
+			break
+		}
+		p.idle.Remove(back)
+		p.total--
+		p.signal()
+		res = append(res, o.obj)
+	}
+	return res
+}
+
+func (p *objectPool[T]) evictLoop() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.config.EvictionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.evict()
+			p.ensureMinIdle()
+		}
+	}
+}
+
+// evict 销毁空闲超过 IdleTimeout 的对象，至少保留 MinIdle 个空闲对象
+func (p *objectPool[T]) evict() {
+	if p.config.IdleTimeout <= 0 {
+		return
+	}
+	deadline := time.Now().Add(-p.config.IdleTimeout)
+	p.mu.Lock()
+	evicted := p.drainIdle(func(o *idleObject[T]) bool {
+		return p.idle.Len() > p.config.MinIdle && o.idleSince.Before(deadline)
+	})
+	p.mu.Unlock()
+	for _, obj := range evicted {
+		p.destroy(obj)
+	}
+}
+
+// ensureMinIdle 空闲对象不足 MinIdle 时补充创建对象，对象总数不会超过 MaxTotal
+func (p *objectPool[T]) ensureMinIdle() {
+	for {
+		p.mu.Lock()
+		if p.closed || p.idle.Len() >= p.config.MinIdle ||
+			(p.config.MaxTotal > 0 && p.total >= p.config.MaxTotal) {
+			p.mu.Unlock()
+			return
+		}
+		p.total++
+		p.mu.Unlock()
+		obj, err := p.factory.OnCreate(context.Background())
+		p.mu.Lock()
+		if err != nil || p.closed {
+			p.total--
+			p.signal()
+			p.mu.Unlock()
+			if err == nil {
+				p.stats.record(EventCreate)
+				p.destroy(obj)
+			}
+			return
+		}
+		p.idle.PushBack(&idleObject[T]{obj: obj, idleSince: time.Now()})
+		p.signal()
+		p.mu.Unlock()
+		p.stats.record(EventCreate)
+	}
+}
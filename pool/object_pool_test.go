@@ -0,0 +1,202 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testObject struct {
+	id    int64
+	valid bool
+}
+
+type testFactory struct {
+	created, destroyed atomic.Int64
+}
+
+func (f *testFactory) OnCreate(ctx context.Context) (*testObject, error) {
+	return &testObject{id: f.created.Add(1), valid: true}, nil
+}
+
+func (f *testFactory) OnValidate(obj *testObject) bool {
+	return obj.valid
+}
+
+func (f *testFactory) OnDestroy(obj *testObject) {
+	f.destroyed.Add(1)
+}
+
+func Test_objectPool_Borrow(t *testing.T) {
+	factory := &testFactory{}
+	p := NewObjectPool[*testObject](factory, ObjectPoolConfig{MaxTotal: 2, TestOnBorrow: true})
+	defer p.Close()
+	ctx := context.Background()
+	o1, _ := p.Borrow(ctx)
+	o2, _ := p.Borrow(ctx)
+	if o1 == o2 {
+		t.Fatalf("Borrow() returned the same object twice")
+	}
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.Borrow(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Borrow() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err := p.Return(o1); err != nil {
+		t.Fatalf("Return() error = %v", err)
+	}
+	if err := p.Return(o1); !errors.Is(err, ErrNotBorrowed) {
+		t.Errorf("Return() error = %v, want %v", err, ErrNotBorrowed)
+	}
+	if o, _ := p.Borrow(ctx); o != o1 {
+		t.Errorf("Borrow() = %v, want idle object %v", o, o1)
+	}
+	// 校验失败的空闲对象被销毁，借出新创建的对象
+	o1.valid = false
+	p.Return(o1)
+	if o, _ := p.Borrow(ctx); o == o1 || factory.destroyed.Load() != 1 {
+		t.Errorf("Borrow() = %v, destroyed = %d", o, factory.destroyed.Load())
+	}
+	if got := factory.created.Load(); got != 3 {
+		t.Errorf("created = %d, want 3", got)
+	}
+}
+
+func Test_objectPool_wait(t *testing.T) {
+	factory := &testFactory{}
+	p := NewObjectPool[*testObject](factory, ObjectPoolConfig{MaxTotal: 3, MaxWait: time.Second})
+	defer p.Close()
+	var wg sync.WaitGroup
+	var maxActive, active atomic.Int64
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				o, err := p.Borrow(context.Background())
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if n := active.Add(1); n > maxActive.Load() {
+					maxActive.Store(n)
+				}
+				active.Add(-1)
+				if j%10 == 0 {
+					p.Invalidate(o)
+				} else {
+					p.Return(o)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if maxActive.Load() > 3 {
+		t.Errorf("max active = %d, want <= 3", maxActive.Load())
+	}
+	if live := factory.created.Load() - factory.destroyed.Load(); live > 3 {
+		t.Errorf("live objects = %d, want <= 3", live)
+	}
+}
+
+func Test_objectPool_Invalidate(t *testing.T) {
+	factory := &testFactory{}
+	p := NewObjectPool[*testObject](factory, ObjectPoolConfig{MaxTotal: 1})
+	o, _ := p.Borrow(context.Background())
+	done := make(chan *testObject)
+	go func() {
+		o, _ := p.Borrow(context.Background())
+		done <- o
+	}()
+	if err := p.Invalidate(o); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	if o2 := <-done; o2 == o || o2 == nil {
+		t.Errorf("Borrow() = %v after Invalidate()", o2)
+	}
+	p.Close()
+	if _, err := p.Borrow(context.Background()); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Borrow() error = %v, want %v", err, ErrPoolClosed)
+	}
+	if factory.destroyed.Load() != 1 {
+		t.Errorf("destroyed = %d, want 1", factory.destroyed.Load())
+	}
+}
+
+func Test_objectPool_evict(t *testing.T) {
+	factory := &testFactory{}
+	p := NewObjectPool[*testObject](factory, ObjectPoolConfig{
+		MaxTotal:         5,
+		MinIdle:          2,
+		IdleTimeout:      time.Millisecond,
+		EvictionInterval: 5 * time.Millisecond,
+	})
+	var objs []*testObject
+	for i := 0; i < 5; i++ {
+		o, _ := p.Borrow(context.Background())
+		objs = append(objs, o)
+	}
+	for _, o := range objs {
+		p.Return(o)
+	}
+	deadline := time.Now().Add(time.Second)
+	for factory.destroyed.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := factory.destroyed.Load(); got != 3 {
+		t.Errorf("destroyed = %d, want 3", got)
+	}
+	p.Close()
+	if got := factory.destroyed.Load(); got != 5 {
+		t.Errorf("destroyed = %d, want 5 after Close()", got)
+	}
+}
+
+func Test_objectPool_Stats(t *testing.T) {
+	factory := &testFactory{}
+	var events [EventWait + 1]atomic.Int64
+	p := NewObjectPool[*testObject](factory, ObjectPoolConfig{
+		MaxTotal: 1,
+		MaxWait:  10 * time.Millisecond,
+		Observer: ObserverFunc(func(e Event) {
+			events[e.Type].Add(1)
+		}),
+	})
+	o, _ := p.Borrow(context.Background())
+	p.Borrow(context.Background())
+	p.Return(o)
+	o, _ = p.Borrow(context.Background())
+	p.Invalidate(o)
+	got := p.Stats()
+	if got.Created != 1 || got.Borrowed != 2 || got.Returned != 1 || got.Destroyed != 1 ||
+		got.Idle != 0 || got.Active != 0 || got.WaitCount != 1 || got.WaitTime < 10*time.Millisecond {
+		t.Errorf("Stats() = %+v", got)
+	}
+	for i, want := range []int64{1, 2, 1, 1, 1} {
+		if events[i].Load() != want {
+			t.Errorf("event %v = %d, want %d", EventType(i), events[i].Load(), want)
+		}
+	}
+	p.Close()
+}
@@ -19,27 +19,137 @@
 package pool
 
 import (
+	"errors"
 	"sync"
 )
 
-func NewPool[T any](f func() T) *Pool[T] {
-	return &Pool[T]{
-		Pool: sync.Pool{
-			New: func() any {
-				return f()
-			},
-		},
+// ErrUseAfterPut 调试模式下发现对象归还到 Pool 之后仍然被修改
+var ErrUseAfterPut = errors.New("object used after put")
+
+// Resetter 对象实现 Resetter 时，归还到 Pool 之前会自动调用 Reset 清理对象的状态
+// 值类型的对象调用 Reset 只会修改副本，需要使用指针类型实现
+type Resetter interface {
+	Reset()
+}
+
+// Poisoner 调试模式下对归还的对象"投毒"，借出时检查投毒后的状态是否被破坏
+type Poisoner[T any] interface {
+	// Poison 使用特殊的值覆盖对象的内容
+	Poison(x T)
+
+	// Poisoned 返回对象是否仍然是 Poison 之后的状态
+	Poisoned(x T) bool
+}
+
+// PoolOption Pool 的可选配置
+type PoolOption[T any] func(p *Pool[T])
+
+// WithObserver 设置 Pool 生命周期事件的观察者
+func WithObserver[T any](observer Observer) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.stats.observer = observer
+	}
+}
+
+// WithReset 设置对象归还到 Pool 之前调用的清理函数，设置后不再调用对象的 Reset 方法
+func WithReset[T any](reset func(x T)) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.reset = reset
+	}
+}
+
+// WithDebug 开启调试模式，对象清理之后使用 poisoner 投毒再归还到 Pool
+// 借出时如果对象不再是投毒后的状态，说明对象在归还之后仍然被使用，Get 会 panic ErrUseAfterPut
+// 调试模式会在每次 Put 时分配内存，只应该在测试中开启
+func WithDebug[T any](poisoner Poisoner[T]) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.poisoner = poisoner
+	}
+}
+
+func NewPool[T any](f func() T, options ...PoolOption[T]) *Pool[T] {
+	p := &Pool[T]{}
+	p.Pool.New = func() any {
+		x := f()
+		p.stats.record(EventCreate)
+		return x
 	}
+	for _, option := range options {
+		option(p)
+	}
+	return p
 }
 
 type Pool[T any] struct {
 	sync.Pool
+	stats    statsCounter
+	reset    func(x T)
+	poisoner Poisoner[T]
+}
+
+// poisonedObject 调试模式下归还到 sync.Pool 的对象，用于区分新创建的对象
+type poisonedObject[T any] struct {
+	x T
 }
 
 func (t *Pool[T]) Get() T {
-	return t.Pool.Get().(T)
+	var x T
+	switch v := t.Pool.Get().(type) {
+	case poisonedObject[T]:
+		if !t.poisoner.Poisoned(v.x) {
+			panic(ErrUseAfterPut)
+		}
+		x = v.x
+	default:
+		x = v.(T)
+	}
+	t.stats.record(EventBorrow)
+	return x
 }
 
+// Put 归还对象，归还之前使用 WithReset 设置的函数或者对象的 Reset 方法清理对象
 func (t *Pool[T]) Put(x T) {
-	t.Pool.Put(x)
+	if t.reset != nil {
+		t.reset(x)
+	} else if r, ok := any(x).(Resetter); ok {
+		r.Reset()
+	}
+	if t.poisoner != nil {
+		t.poisoner.Poison(x)
+		t.Pool.Put(poisonedObject[T]{x: x})
+	} else {
+		t.Pool.Put(x)
+	}
+	t.stats.record(EventReturn)
+}
+
+// Stats 返回统计信息快照
+// sync.Pool 中的空闲对象随时可能被 GC 回收并且不会通知 Pool，所以 Idle 是空闲对象个数的上限，Destroyed 总是 0
+func (t *Pool[T]) Stats() Stats {
+	s := t.stats.snapshot()
+	s.Active = int(max(s.Borrowed-s.Returned, 0))
+	s.Idle = int(max(s.Returned-(s.Borrowed-s.Created), 0))
+	return s
+}
+
+// poisonByte 调试模式下填充字节切片的值
+const poisonByte = 0xdb
+
+// BytesPoisoner 使用 0xdb 填充字节切片的整个底层数组（到 cap 为止）
+type BytesPoisoner struct{}
+
+func (BytesPoisoner) Poison(b []byte) {
+	b = b[:cap(b)]
+	for i := range b {
+		b[i] = poisonByte
+	}
+}
+
+func (BytesPoisoner) Poisoned(b []byte) bool {
+	for _, c := range b[:cap(b)] {
+		if c != poisonByte {
+			return false
+		}
+	}
+	return true
 }
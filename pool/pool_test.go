@@ -12,3 +12,98 @@ func TestNewPool(t *testing.T) {
 		t.Errorf("Get() = %v, want 10", p.Get())
 	}
 }
+
+func TestPool_Stats(t *testing.T) {
+	var events []EventType
+	p := NewPool(func() []byte {
+		return make([]byte, 8)
+	}, WithObserver[[]byte](ObserverFunc(func(e Event) {
+		events = append(events, e.Type)
+	})))
+	b := p.Get()
+	got := p.Stats()
+	if got.Created != 1 || got.Borrowed != 1 || got.Active != 1 || got.Idle != 0 {
+		t.Errorf("Stats() = %+v", got)
+	}
+	p.Put(b)
+	got = p.Stats()
+	if got.Returned != 1 || got.Active != 0 || got.Idle != 1 {
+		t.Errorf("Stats() = %+v", got)
+	}
+	if len(events) != 3 || events[0] != EventCreate || events[1] != EventBorrow || events[2] != EventReturn {
+		t.Errorf("events = %v", events)
+	}
+}
+
+type resetBuffer struct {
+	data []int
+}
+
+func (b *resetBuffer) Reset() {
+	b.data = b.data[:0]
+}
+
+func TestPool_Reset(t *testing.T) {
+	p := NewPool(func() *resetBuffer {
+		return &resetBuffer{}
+	})
+	b := p.Get()
+	b.data = append(b.data, 1, 2, 3)
+	p.Put(b)
+	if len(b.data) != 0 {
+		t.Errorf("Put() did not call Reset, data = %v", b.data)
+	}
+
+	called := 0
+	p2 := NewPool(func() *resetBuffer {
+		return &resetBuffer{}
+	}, WithReset(func(b *resetBuffer) {
+		called++
+		b.data = nil
+	}))
+	p2.Put(&resetBuffer{data: []int{1}})
+	if called != 1 {
+		t.Errorf("reset called %d times, want 1", called)
+	}
+}
+
+func TestBytesPoisoner(t *testing.T) {
+	b := make([]byte, 2, 4)
+	BytesPoisoner{}.Poison(b)
+	if !(BytesPoisoner{}).Poisoned(b) {
+		t.Fatalf("Poisoned() = false after Poison(), b = %v", b[:cap(b)])
+	}
+	// 写入 len 之外、cap 之内的位置同样可以被发现
+	b[:cap(b)][3] = 0
+	if (BytesPoisoner{}).Poisoned(b) {
+		t.Errorf("Poisoned() = true after write")
+	}
+}
+
+func TestPool_Debug(t *testing.T) {
+	p := NewPool(func() []byte {
+		return make([]byte, 4)
+	}, WithDebug[[]byte](BytesPoisoner{}))
+	b := p.Get()
+	p.Put(b)
+	for _, c := range b {
+		if c != poisonByte {
+			t.Fatalf("Put() did not poison %v", b)
+		}
+	}
+	// sync.Pool 随时可能丢弃归还的对象，直接使用 New 返回投毒后的对象，模拟从 Pool 中取回
+	p.Pool.New = func() any {
+		return poisonedObject[[]byte]{x: b}
+	}
+	if got := p.Get(); &got[0] != &b[0] {
+		t.Fatalf("Get() did not return the poisoned object")
+	}
+	// 归还之后继续写入
+	b[0] = 1
+	defer func() {
+		if r := recover(); r != ErrUseAfterPut {
+			t.Errorf("Get() panic = %v, want %v", r, ErrUseAfterPut)
+		}
+	}()
+	p.Get()
+}
@@ -0,0 +1,151 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pool
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats 对象池的统计信息快照
+type Stats struct {
+	// Created 累计创建的对象个数
+	Created int64
+	// Borrowed 累计借出的对象个数
+	Borrowed int64
+	// Returned 累计归还的对象个数
+	Returned int64
+	// Destroyed 累计销毁的对象个数
+	Destroyed int64
+	// Idle 当前空闲的对象个数
+	Idle int
+	// Active 当前借出的对象个数
+	Active int
+	// WaitCount 借出对象时需要等待的累计次数
+	WaitCount int64
+	// WaitTime 借出对象时累计的等待时间
+	WaitTime time.Duration
+}
+
+// EventType 对象池生命周期事件的类型
+type EventType int
+
+const (
+	// EventCreate 创建了新对象
+	EventCreate EventType = iota
+	// EventBorrow 借出了对象
+	EventBorrow
+	// EventReturn 归还了对象
+	EventReturn
+	// EventDestroy 销毁了对象
+	EventDestroy
+	// EventWait 借出对象时等待结束，无论是否成功借出
+	EventWait
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventCreate:
+		return "create"
+	case EventBorrow:
+		return "borrow"
+	case EventReturn:
+		return "return"
+	case EventDestroy:
+		return "destroy"
+	case EventWait:
+		return "wait"
+	}
+	return "unknown"
+}
+
+// Event 对象池生命周期事件
+type Event struct {
+	Type EventType
+	// Wait 等待的时间，只有 EventWait 事件有效
+	Wait time.Duration
+}
+
+// Observer 对象池生命周期事件的观察者
+// 事件在触发事件的协程中同步通知，并且可能被多个协程同时调用，实现需要保证并发安全并且尽快返回
+type Observer interface {
+	OnEvent(e Event)
+}
+
+// ObserverFunc 使用函数实现 Observer
+type ObserverFunc func(e Event)
+
+func (f ObserverFunc) OnEvent(e Event) {
+	f(e)
+}
+
+// statsCounter 记录对象池的累计统计信息并通知观察者
+type statsCounter struct {
+	created, borrowed, returned, destroyed atomic.Int64
+	waitCount, waitTime                    atomic.Int64
+	observer                               Observer
+}
+
+func (s *statsCounter) record(t EventType) {
+	switch t {
+	case EventCreate:
+		s.created.Add(1)
+	case EventBorrow:
+		s.borrowed.Add(1)
+	case EventReturn:
+		s.returned.Add(1)
+	case EventDestroy:
+		s.destroyed.Add(1)
+	}
+	if s.observer != nil {
+		s.observer.OnEvent(Event{Type: t})
+	}
+}
+
+func (s *statsCounter) recordWait(wait time.Duration) {
+	s.waitCount.Add(1)
+	s.waitTime.Add(int64(wait))
+	if s.observer != nil {
+		s.observer.OnEvent(Event{Type: EventWait, Wait: wait})
+	}
+}
+
+// snapshot 返回累计统计信息，Idle 和 Active 由调用方填充
+func (s *statsCounter) snapshot() Stats {
+	return Stats{
+		Created:   s.created.Load(),
+		Borrowed:  s.borrowed.Load(),
+		Returned:  s.returned.Load(),
+		Destroyed: s.destroyed.Load(),
+		WaitCount: s.waitCount.Load(),
+		WaitTime:  time.Duration(s.waitTime.Load()),
+	}
+}
+
+// merge 将 o 累加到 s 中
+func (s *Stats) merge(o Stats) {
+	s.Created += o.Created
+	s.Borrowed += o.Borrowed
+	s.Returned += o.Returned
+	s.Destroyed += o.Destroyed
+	s.Idle += o.Idle
+	s.Active += o.Active
+	s.WaitCount += o.WaitCount
+	s.WaitTime += o.WaitTime
+}
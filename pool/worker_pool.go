@@ -0,0 +1,335 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrTaskCanceled 任务在运行之前被 ShutdownNow 取消
+var ErrTaskCanceled = errors.New("task is canceled")
+
+// PanicError 任务运行时发生 panic，Value 为 recover 的返回值
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panic: %v\n%s", e.Value, e.Stack)
+}
+
+// Unwrap Value 为 error 时返回 Value
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Future 异步任务的结果
+type Future[T any] interface {
+	// Get 等待任务结束并返回任务的结果，ctx 先结束时返回 ctx.Err()
+	Get(ctx context.Context) (T, error)
+
+	// Done 任务结束时关闭的 channel
+	Done() <-chan struct{}
+}
+
+// WorkerPoolConfig WorkerPool 的配置
+type WorkerPoolConfig struct {
+	// MinWorkers 常驻的 worker 个数
+	MinWorkers int
+	// MaxWorkers 最多同时运行的 worker 个数，小于 MinWorkers 时与 MinWorkers 相同
+	// 大于 MinWorkers 时为弹性 worker 池：提交任务时没有空闲的 worker 就创建新的 worker，超出 MinWorkers 的 worker 空闲 KeepAlive 后退出
+	// MinWorkers 和 MaxWorkers 都小于等于 0 时使用 runtime.NumCPU()
+	MaxWorkers int
+	// QueueSize 任务队列的容量，队列已满时提交任务会阻塞
+	QueueSize int
+	// KeepAlive 弹性 worker 的最长空闲时间，小于等于 0 时使用 1 分钟
+	KeepAlive time.Duration
+	// PanicHandler 处理 Execute 提交的任务发生的 panic，为 nil 时忽略，Submit 提交的任务的 panic 通过 Future 返回
+	PanicHandler func(err *PanicError)
+}
+
+// WorkerPool 使用固定或者弹性个数的协程运行有界队列中的任务
+type WorkerPool interface {
+	// Execute 提交不需要结果的任务，队列已满时阻塞直到 ctx 结束
+	// 任务的 ctx 在 ShutdownNow 时被取消，与提交任务时的 ctx 无关
+	Execute(ctx context.Context, task func(ctx context.Context)) error
+
+	// Shutdown 不再接受新的任务，等待已经提交的任务运行结束，ctx 先结束时返回 ctx.Err()，任务会在后台继续运行
+	Shutdown(ctx context.Context) error
+
+	// ShutdownNow 不再接受新的任务，取消正在运行的任务的 ctx，队列中还没有运行的任务不再运行
+	// Submit 提交的任务的 Future 返回 ErrTaskCanceled，ShutdownNow 不会等待正在运行的任务结束
+	ShutdownNow()
+
+	// submit 提交任务，cancel 不为 nil 时在任务被 ShutdownNow 取消时调用
+	submit(ctx context.Context, run func(ctx context.Context), cancel func()) error
+}
+
+// Submit 向 p 提交有返回值的任务，任务的 panic 会被转换为 *PanicError
+func Submit[T any](ctx context.Context, p WorkerPool, task func(ctx context.Context) (T, error)) (Future[T], error) {
+	f := &future[T]{done: make(chan struct{})}
+	run := func(ctx context.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				var zero T
+				f.complete(zero, &PanicError{Value: r, Stack: debug.Stack()})
+			}
+		}()
+		f.complete(task(ctx))
+	}
+	cancel := func() {
+		var zero T
+		f.complete(zero, ErrTaskCanceled)
+	}
+	if err := p.submit(ctx, run, cancel); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+type future[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+func (f *future[T]) complete(val T, err error) {
+	f.val, f.err = val, err
+	close(f.done)
+}
+
+func (f *future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func (f *future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+func NewWorkerPool(config WorkerPoolConfig) WorkerPool {
+	if config.MinWorkers <= 0 && config.MaxWorkers <= 0 {
+		config.MinWorkers = runtime.NumCPU()
+	}
+	config.MinWorkers = max(config.MinWorkers, 0)
+	config.MaxWorkers = max(config.MaxWorkers, config.MinWorkers)
+	config.QueueSize = max(config.QueueSize, 0)
+	if config.KeepAlive <= 0 {
+		config.KeepAlive = time.Minute
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &workerPool{
+		config: config,
+		tasks:  make(chan *workerTask, config.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	p.mu.Lock()
+	for i := 0; i < config.MinWorkers; i++ {
+		p.startWorker()
+	}
+	p.mu.Unlock()
+	return p
+}
+
+type workerTask struct {
+	run    func(ctx context.Context)
+	cancel func()
+}
+
+type workerPool struct {
+	config WorkerPoolConfig
+	tasks  chan *workerTask
+	// ctx 任务运行时使用的 ctx，ShutdownNow 时取消
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	closed  bool
+	workers int
+	// idle 等待任务的 worker 个数
+	idle atomic.Int32
+	// pending 正在向 tasks 发送任务的 submit 调用个数，不为 0 时弹性 worker 不会退出
+	pending int
+	// submitting 正在向 tasks 发送任务的 submit 调用，全部结束之后才能关闭 tasks
+	submitting sync.WaitGroup
+	closeOnce  sync.Once
+	// running 运行中的 worker
+	running sync.WaitGroup
+}
+
+func (p *workerPool) Execute(ctx context.Context, task func(ctx context.Context)) error {
+	return p.submit(ctx, func(ctx context.Context) {
+		defer func() {
+			if r := recover(); r != nil && p.config.PanicHandler != nil {
+				p.config.PanicHandler(&PanicError{Value: r, Stack: debug.Stack()})
+			}
+		}()
+		task(ctx)
+	}, nil)
+}
+
+func (p *workerPool) submit(ctx context.Context, run func(ctx context.Context), cancel func()) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPoolClosed
+	}
+	if p.idle.Load() == 0 && p.workers < p.config.MaxWorkers {
+		p.startWorker()
+	}
+	p.pending++
+	p.submitting.Add(1)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.pending--
+		p.mu.Unlock()
+		p.submitting.Done()
+	}()
+	t := &workerTask{run: run, cancel: cancel}
+	select {
+	case p.tasks <- t:
+		return nil
+	default:
+	}
+	// 队列已满，或者 worker 还没有开始接收任务（例如刚刚启动的 worker），没有达到 MaxWorkers 时再启动一个 worker
+	// 后一种情况可能多启动 worker，多余的弹性 worker 空闲 KeepAlive 后退出
+	p.mu.Lock()
+	if p.workers < p.config.MaxWorkers {
+		p.startWorker()
+	}
+	p.mu.Unlock()
+	select {
+	case p.tasks <- t:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.ctx.Done():
+		return ErrPoolClosed
+	}
+}
+
+func (p *workerPool) Shutdown(ctx context.Context) error {
+	p.reject()
+	done := make(chan struct{})
+	go func() {
+		// 正在提交的任务可能阻塞在已满的队列上，关闭队列也需要在后台等待，避免 ctx 结束后 Shutdown 仍然阻塞
+		p.close()
+		p.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *workerPool) ShutdownNow() {
+	p.cancel()
+	p.close()
+}
+
+// reject 不再接受新的任务
+func (p *workerPool) reject() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+}
+
+// close 拒绝新的任务，等待正在提交的任务进入队列之后关闭队列，worker 运行完队列中的任务后退出
+func (p *workerPool) close() {
+	p.reject()
+	p.closeOnce.Do(func() {
+		// 关闭队列之前没有 worker 时，正在提交的任务会一直阻塞
+		p.mu.Lock()
+		if p.workers == 0 {
+			p.startWorker()
+		}
+		p.mu.Unlock()
+		p.submitting.Wait()
+		close(p.tasks)
+	})
+}
+
+// startWorker 调用时需要持有 mu
+func (p *workerPool) startWorker() {
+	p.workers++
+	p.running.Add(1)
+	go p.work()
+}
+
+func (p *workerPool) work() {
+	defer p.running.Done()
+	timer := time.NewTimer(p.config.KeepAlive)
+	defer timer.Stop()
+	for {
+		p.idle.Add(1)
+		select {
+		case t, ok := <-p.tasks:
+			p.idle.Add(-1)
+			if !ok {
+				p.mu.Lock()
+				p.workers--
+				p.mu.Unlock()
+				return
+			}
+			if p.ctx.Err() != nil {
+				if t.cancel != nil {
+					t.cancel()
+				}
+			} else {
+				t.run(p.ctx)
+			}
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(p.config.KeepAlive)
+		case <-timer.C:
+			p.idle.Add(-1)
+			p.mu.Lock()
+			if p.workers > p.config.MinWorkers && p.pending == 0 && len(p.tasks) == 0 {
+				p.workers--
+				p.mu.Unlock()
+				return
+			}
+			p.mu.Unlock()
+			timer.Reset(p.config.KeepAlive)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+/*
+ *
+ * Copyright 2022 go-util authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_workerPool_Submit(t *testing.T) {
+	p := NewWorkerPool(WorkerPoolConfig{MinWorkers: 2, MaxWorkers: 4, QueueSize: 8})
+	ctx := context.Background()
+	var running, maxRunning atomic.Int32
+	var futures []Future[int]
+	for i := 0; i < 50; i++ {
+		i := i
+		f, err := Submit(ctx, p, func(ctx context.Context) (int, error) {
+			n := running.Add(1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return i * i, nil
+		})
+		if err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+		futures = append(futures, f)
+	}
+	for i, f := range futures {
+		if got, err := f.Get(ctx); err != nil || got != i*i {
+			t.Errorf("Get() = %v, %v, want %v", got, err, i*i)
+		}
+	}
+	if maxRunning.Load() > 4 {
+		t.Errorf("max running = %d, want <= 4", maxRunning.Load())
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	if _, err := Submit(ctx, p, func(ctx context.Context) (int, error) { return 0, nil }); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit() error = %v, want %v", err, ErrPoolClosed)
+	}
+}
+
+func Test_workerPool_panic(t *testing.T) {
+	var handled atomic.Value
+	p := NewWorkerPool(WorkerPoolConfig{MinWorkers: 1, PanicHandler: func(err *PanicError) {
+		handled.Store(err.Value)
+	}})
+	defer p.ShutdownNow()
+	ctx := context.Background()
+	errBoom := errors.New("boom")
+	f, _ := Submit(ctx, p, func(ctx context.Context) (string, error) {
+		panic(errBoom)
+	})
+	_, err := f.Get(ctx)
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || !errors.Is(err, errBoom) {
+		t.Errorf("Get() error = %v, want PanicError", err)
+	}
+	p.Execute(ctx, func(ctx context.Context) {
+		panic("execute")
+	})
+	// worker 在 panic 之后仍然可以运行任务
+	f2, _ := Submit(ctx, p, func(ctx context.Context) (string, error) {
+		return "ok", nil
+	})
+	if got, err := f2.Get(ctx); got != "ok" || err != nil {
+		t.Errorf("Get() = %v, %v", got, err)
+	}
+	if handled.Load() != "execute" {
+		t.Errorf("PanicHandler got %v, want execute", handled.Load())
+	}
+}
+
+func Test_workerPool_Shutdown(t *testing.T) {
+	p := NewWorkerPool(WorkerPoolConfig{MinWorkers: 1, QueueSize: 10})
+	var done atomic.Int32
+	for i := 0; i < 10; i++ {
+		p.Execute(context.Background(), func(ctx context.Context) {
+			time.Sleep(time.Millisecond)
+			done.Add(1)
+		})
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if done.Load() != 10 {
+		t.Errorf("done = %d, want 10", done.Load())
+	}
+}
+
+func Test_workerPool_ShutdownBlockedSubmit(t *testing.T) {
+	p := NewWorkerPool(WorkerPoolConfig{MinWorkers: 1, QueueSize: 1})
+	ctx := context.Background()
+	started, release := make(chan struct{}), make(chan struct{})
+	p.Execute(ctx, func(ctx context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+	p.Execute(ctx, func(ctx context.Context) {})
+	// 队列已满，第三个任务阻塞在提交上
+	submitted := make(chan error, 1)
+	go func() {
+		submitted <- p.Execute(ctx, func(ctx context.Context) {})
+	}()
+	for {
+		wp := p.(*workerPool)
+		wp.mu.Lock()
+		pending := wp.pending
+		wp.mu.Unlock()
+		if pending == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	shutdownCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	begin := time.Now()
+	if err := p.Shutdown(shutdownCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if d := time.Since(begin); d > 500*time.Millisecond {
+		t.Errorf("Shutdown() took %v after ctx deadline", d)
+	}
+	if err := p.Execute(ctx, func(ctx context.Context) {}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Execute() error = %v, want %v", err, ErrPoolClosed)
+	}
+	close(release)
+	if err := p.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	if err := <-submitted; err != nil {
+		t.Errorf("Execute() error = %v, want nil", err)
+	}
+}
+
+func Test_workerPool_ShutdownNow(t *testing.T) {
+	p := NewWorkerPool(WorkerPoolConfig{MinWorkers: 1, QueueSize: 10})
+	ctx := context.Background()
+	started := make(chan struct{})
+	running, _ := Submit(ctx, p, func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	<-started
+	queued, _ := Submit(ctx, p, func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	// 队列已满时提交任务阻塞直到 ctx 结束
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	full := NewWorkerPool(WorkerPoolConfig{MinWorkers: 1})
+	full.Execute(ctx, func(ctx context.Context) { <-ctx.Done() })
+	if err := full.Execute(timeout, func(ctx context.Context) {}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Execute() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	full.ShutdownNow()
+
+	p.ShutdownNow()
+	if _, err := running.Get(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Get() error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := queued.Get(ctx); !errors.Is(err, ErrTaskCanceled) {
+		t.Errorf("Get() error = %v, want %v", err, ErrTaskCanceled)
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
+
+func Test_workerPool_elastic(t *testing.T) {
+	p := NewWorkerPool(WorkerPoolConfig{MaxWorkers: 4, KeepAlive: 10 * time.Millisecond}).(*workerPool)
+	ctx := context.Background()
+	release := make(chan struct{})
+	var futures []Future[struct{}]
+	for i := 0; i < 4; i++ {
+		f, _ := Submit(ctx, p, func(ctx context.Context) (struct{}, error) {
+			<-release
+			return struct{}{}, nil
+		})
+		futures = append(futures, f)
+	}
+	p.mu.Lock()
+	workers := p.workers
+	p.mu.Unlock()
+	if workers != 4 {
+		t.Errorf("workers = %d, want 4", workers)
+	}
+	close(release)
+	for _, f := range futures {
+		f.Get(ctx)
+	}
+	deadline := time.Now().Add(time.Second)
+	for workers > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		p.mu.Lock()
+		workers = p.workers
+		p.mu.Unlock()
+	}
+	if workers != 0 {
+		t.Errorf("workers = %d after KeepAlive, want 0", workers)
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}